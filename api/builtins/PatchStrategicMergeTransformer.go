@@ -5,6 +5,8 @@ package builtins
 
 import (
 	"fmt"
+	"log"
+	"reflect"
 
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
@@ -14,8 +16,22 @@ import (
 
 type PatchStrategicMergeTransformerPlugin struct {
 	loadedPatches []*resource.Resource
-	Paths         []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
-	Patches       string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+	// The loaded patches before merging, when checking for no-ops.
+	writtenPatches []*resource.Resource
+	// Where each loaded patch came from, for messages.
+	patchSources []string
+	Paths        []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Patches      string                      `json:"patches,omitempty" yaml:"patches,omitempty"`
+
+	// WarnOnNoOp, if true, logs a warning for every patch that
+	// leaves its target unchanged, as such a patch is likely stale.
+	WarnOnNoOp bool `json:"warnOnNoOp,omitempty" yaml:"warnOnNoOp,omitempty"`
+
+	// ReportChanges, if true, notes the fields each patch
+	// changes, for PatchReports.
+	ReportChanges bool `json:"reportChanges,omitempty" yaml:"reportChanges,omitempty"`
+
+	reports []types.PatchReport
 }
 
 func (p *PatchStrategicMergeTransformerPlugin) Config(
@@ -36,7 +52,7 @@ func (p *PatchStrategicMergeTransformerPlugin) Config(
 			// exists for this purpose (inline patch declaration).
 			res, err := h.ResmapFactory().RF().SliceFromBytes([]byte(onePath))
 			if err == nil {
-				p.loadedPatches = append(p.loadedPatches, res...)
+				p.addPatches(res, "inline patch")
 				continue
 			}
 			res, err = h.ResmapFactory().RF().SliceFromPatches(
@@ -44,7 +60,7 @@ func (p *PatchStrategicMergeTransformerPlugin) Config(
 			if err != nil {
 				return err
 			}
-			p.loadedPatches = append(p.loadedPatches, res...)
+			p.addPatches(res, string(onePath))
 		}
 	}
 	if p.Patches != "" {
@@ -52,13 +68,20 @@ func (p *PatchStrategicMergeTransformerPlugin) Config(
 		if err != nil {
 			return err
 		}
-		p.loadedPatches = append(p.loadedPatches, res...)
+		p.addPatches(res, "inline patch")
 	}
 
 	if len(p.loadedPatches) == 0 {
 		return fmt.Errorf(
 			"patch appears to be empty; files=%v, Patch=%s", p.Paths, p.Patches)
 	}
+	if p.WarnOnNoOp {
+		// Merging changes the patches, so keep them as written.
+		p.writtenPatches = make([]*resource.Resource, len(p.loadedPatches))
+		for i, patch := range p.loadedPatches {
+			p.writtenPatches[i] = patch.DeepCopy()
+		}
+	}
 	// Merge the patches, looking for conflicts.
 	_, err = h.ResmapFactory().ConflatePatches(p.loadedPatches)
 	if err != nil {
@@ -67,16 +90,81 @@ func (p *PatchStrategicMergeTransformerPlugin) Config(
 	return nil
 }
 
+func (p *PatchStrategicMergeTransformerPlugin) addPatches(patches []*resource.Resource, source string) {
+	for range patches {
+		p.patchSources = append(p.patchSources, source)
+	}
+	p.loadedPatches = append(p.loadedPatches, patches...)
+}
+
 func (p *PatchStrategicMergeTransformerPlugin) Transform(m resmap.ResMap) error {
-	for _, patch := range p.loadedPatches {
+	if p.WarnOnNoOp {
+		if err := p.warnOnNoOp(m); err != nil {
+			return err
+		}
+	}
+	p.reports = nil
+	for i, patch := range p.loadedPatches {
 		target, err := m.GetById(patch.OrgId())
 		if err != nil {
 			return err
 		}
+		var before map[string]interface{}
+		if p.ReportChanges {
+			if before, err = target.Map(); err != nil {
+				return err
+			}
+		}
 		if err = m.ApplySmPatch(
 			resource.MakeIdSet([]*resource.Resource{target}), patch); err != nil {
 			return err
 		}
+		if p.ReportChanges {
+			after, err := target.Map()
+			if err != nil {
+				return err
+			}
+			p.reports = append(p.reports, types.PatchReport{
+				Patch:  p.patchSources[i],
+				Target: target.CurId(),
+				Fields: resource.ChangedFields(before, after),
+			})
+		}
+	}
+	return nil
+}
+
+// PatchReports implements resmap.PatchReporter.
+func (p *PatchStrategicMergeTransformerPlugin) PatchReports() []types.PatchReport {
+	return p.reports
+}
+
+// warnOnNoOp logs a warning for each patch that, applied by
+// itself, would leave its target unchanged.
+func (p *PatchStrategicMergeTransformerPlugin) warnOnNoOp(m resmap.ResMap) error {
+	for i, patch := range p.writtenPatches {
+		target, err := m.GetById(patch.OrgId())
+		if err != nil {
+			return err
+		}
+		before, err := target.Map()
+		if err != nil {
+			return err
+		}
+		patched := target.DeepCopy()
+		if err = patched.ApplySmPatch(patch.DeepCopy()); err != nil {
+			// Errors, e.g. from deleting the target, are left to
+			// the actual application of the patch.
+			continue
+		}
+		after, err := patched.Map()
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(before, after) {
+			log.Printf("patch %s left %s unchanged",
+				p.patchSources[i], target.CurId())
+		}
 	}
 	return nil
 }
@@ -658,6 +658,56 @@ spec:
 				},
 			},
 		},
+		"rotateDigest": {
+			input: `
+apiVersion: v1
+kind: Deployment
+metadata:
+  name: deploy1
+spec:
+  template:
+    spec:
+      containers:
+      - image: app@sha256:old
+        name: pinned-old
+      - image: app@sha256:older
+        name: pinned-other
+      - image: app:1.0
+        name: tagged
+      - image: other@sha256:old
+        name: other-image
+`,
+			expectedOutput: `
+apiVersion: v1
+kind: Deployment
+metadata:
+  name: deploy1
+spec:
+  template:
+    spec:
+      containers:
+      - image: app@sha256:new
+        name: pinned-old
+      - image: app@sha256:older
+        name: pinned-other
+      - image: app:1.0
+        name: tagged
+      - image: other@sha256:old
+        name: other-image
+`,
+			filter: Filter{
+				ImageTag: types.Image{
+					Name:      "app",
+					Digest:    "sha256:old",
+					NewDigest: "sha256:new",
+				},
+			},
+			fsSlice: []types.FieldSpec{
+				{
+					Path: "spec/template/spec/containers[]/image",
+				},
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -29,6 +29,16 @@ func (u imageTagUpdater) Filter(rn *yaml.RNode) (*yaml.RNode, error) {
 	}
 
 	name, tag := image.Split(value)
+	if u.ImageTag.NewDigest != "" {
+		if u.ImageTag.Digest != "" && tag != "@"+u.ImageTag.Digest {
+			return rn, nil
+		}
+		if u.ImageTag.NewName != "" {
+			name = u.ImageTag.NewName
+		}
+		return rn.Pipe(yaml.FieldSetter{
+			StringValue: name + "@" + u.ImageTag.NewDigest})
+	}
 	if u.ImageTag.NewName != "" {
 		name = u.ImageTag.NewName
 	}
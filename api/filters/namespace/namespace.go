@@ -18,6 +18,12 @@ type Filter struct {
 
 	// FsSlice contains the FieldSpecs to locate the namespace field
 	FsSlice types.FsSlice `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+
+	// IsOwnedServiceAccount, if set, reports whether the ServiceAccount
+	// with the given namespace and name is among the resources being
+	// transformed.  RoleBinding subjects that explicitly name the
+	// namespace of some other ServiceAccount are then left alone.
+	IsOwnedServiceAccount func(namespace, name string) bool `json:"-" yaml:"-"`
 }
 
 var _ kio.Filter = Filter{}
@@ -95,6 +101,8 @@ func (ns Filter) metaNamespaceHack(obj *yaml.RNode, meta yaml.ResourceMeta) erro
 // RoleBinding and ClusterRoleBinding have namespace set on
 // elements of the "subjects" field if and only if the subject elements
 // "name" is "default".  Otherwise the namespace is not set.
+// A subject already naming a namespace keeps it, unless
+// IsOwnedServiceAccount says its account is part of the build.
 //
 // Example:
 //
@@ -129,6 +137,18 @@ func (ns Filter) roleBindingHack(obj *yaml.RNode, meta yaml.ResourceMeta) error
 			return err
 		}
 
+		// leave alone accounts from outside the build
+		if ns.IsOwnedServiceAccount != nil {
+			subjectNs, err := o.Pipe(yaml.Lookup("namespace"))
+			if err != nil {
+				return err
+			}
+			if !yaml.IsMissingOrNull(subjectNs) &&
+				!ns.IsOwnedServiceAccount(subjectNs.YNode().Value, "default") {
+				return nil
+			}
+		}
+
 		// set the namespace for the default account
 		v := yaml.NewScalarRNode(ns.Namespace)
 		return o.PipeE(
@@ -243,6 +243,37 @@ subjects:
 		filter: namespace.Filter{Namespace: "bar"},
 	},
 
+	{
+		name: "update-clusterrolebinding-owned-accounts-only",
+		input: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+subjects:
+- name: default
+- name: default
+  namespace: foo
+- name: default
+  namespace: kube-system
+`,
+		expected: `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+subjects:
+- name: default
+  namespace: bar
+- name: default
+  namespace: bar
+- name: default
+  namespace: kube-system
+`,
+		filter: namespace.Filter{
+			Namespace: "bar",
+			IsOwnedServiceAccount: func(namespace, name string) bool {
+				return namespace == "foo" && name == "default"
+			},
+		},
+	},
+
 	{
 		name: "data-fieldspecs",
 		input: `
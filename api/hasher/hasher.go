@@ -26,11 +26,16 @@ func SortArrayAndComputeHash(s []string) (string, error) {
 // Copied from https://github.com/kubernetes/kubernetes
 // /blob/master/pkg/kubectl/util/hash/hash.go
 func Encode(hex string) (string, error) {
-	if len(hex) < 10 {
+	return EncodeN(hex, 10)
+}
+
+// EncodeN is Encode for the first n characters of the hex.
+func EncodeN(hex string, n int) (string, error) {
+	if len(hex) < n {
 		return "", fmt.Errorf(
-			"input length must be at least 10")
+			"input length must be at least %d", n)
 	}
-	enc := []rune(hex[:10])
+	enc := []rune(hex[:n])
 	for i := range enc {
 		switch enc[i] {
 		case '0':
@@ -91,12 +96,19 @@ func getNodeValues(node *yaml.RNode, paths []string) (map[string]interface{}, er
 			values[p] = ""
 			continue
 		}
-		if vn.YNode().Kind != yaml.ScalarNode {
+		if vn.YNode().Kind == yaml.MappingNode {
+			// data, binaryData and stringData are all maps
+			// of strings; see canonicalStringMap.
+			v, err := canonicalStringMap(vn)
+			if err != nil {
+				return map[string]interface{}{}, err
+			}
+			values[p] = v
+		} else if vn.YNode().Kind != yaml.ScalarNode {
 			vs, err := vn.MarshalJSON()
 			if err != nil {
 				return map[string]interface{}{}, err
 			}
-			// data, binaryData and stringData are all maps
 			var v map[string]interface{}
 			json.Unmarshal(vs, &v)
 			values[p] = v
@@ -107,6 +119,32 @@ func getNodeValues(node *yaml.RNode, paths []string) (map[string]interface{}, er
 	return values, nil
 }
 
+// canonicalStringMap returns the map held by the node with
+// every scalar value taken as the string it's written as,
+// so that e.g. `port: 8080` and `port: "8080"` encode the
+// same way, as they mean the same thing to the API server.
+func canonicalStringMap(node *yaml.RNode) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	err := node.VisitFields(func(n *yaml.MapNode) error {
+		k := n.Key.YNode().Value
+		if n.Value.YNode().Kind == yaml.ScalarNode {
+			result[k] = n.Value.YNode().Value
+			return nil
+		}
+		vs, err := n.Value.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		var v interface{}
+		if err = json.Unmarshal(vs, &v); err != nil {
+			return err
+		}
+		result[k] = v
+		return nil
+	})
+	return result, err
+}
+
 // encodeConfigMap encodes a ConfigMap.
 // Data, Kind, and Name are taken into account.
 // BinaryData is included if it's not empty to avoid useless key in output.
@@ -65,7 +65,7 @@ kind: ConfigMap
 data:
   two: 2
   one: ""
-  three: 3`, "7757f9kkct", ""},
+  three: 3`, "f5h7t85m9b", ""},
 		// empty binary data map
 		{"empty binary data", `
 apiVersion: v1
@@ -83,7 +83,7 @@ kind: ConfigMap
 binaryData:
   two: 2
   one: ""
-  three: 3`, "9th7kc28dg", ""},
+  three: 3`, "57k9c9gm4b", ""},
 		// two keys, one with string and another with binary data
 		{"two keys with one each", `
 apiVersion: v1
@@ -92,6 +92,22 @@ data:
   one: ""
 binaryData:
   two: ""`, "698h7c7t9m", ""},
+		// quoting doesn't change the value
+		{"quoted number", `
+apiVersion: v1
+kind: ConfigMap
+data:
+  port: "8080"`, "9hdd6gt8fb", ""},
+		{"unquoted number", `
+apiVersion: v1
+kind: ConfigMap
+data:
+  port: 8080`, "9hdd6gt8fb", ""},
+		{"single quoted number", `
+apiVersion: v1
+kind: ConfigMap
+data:
+  port: '8080'`, "9hdd6gt8fb", ""},
 	}
 
 	for _, c := range cases {
@@ -136,7 +152,7 @@ type: my-type
 data:
   two: 2
   one: ""
-  three: 3`, "4gf75c7476", ""},
+  three: 3`, "mbctdb65g2", ""},
 		// with stringdata
 		{"stringdata", `
 apiVersion: v1
@@ -145,7 +161,7 @@ type: my-type
 data:
   one: ""
 stringData:
-  two: 2`, "c4h4264gdb", ""},
+  two: 2`, "ckm7f798g2", ""},
 		// empty stringdata
 		{"empty stringdata", `
 apiVersion: v1
@@ -231,7 +247,7 @@ kind: ConfigMap
 data:
   two: 2
   one: ""
-  three: 3`, `{"data":{"one":"","three":3,"two":2},"kind":"ConfigMap","name":""}`, ""},
+  three: 3`, `{"data":{"one":"","three":"3","two":"2"},"kind":"ConfigMap","name":""}`, ""},
 		// empty binary map
 		{"empty data", `
 apiVersion: v1
@@ -249,7 +265,7 @@ kind: ConfigMap
 binaryData:
   two: 2
   one: ""
-  three: 3`, `{"binaryData":{"one":"","three":3,"two":2},"data":"","kind":"ConfigMap","name":""}`, ""},
+  three: 3`, `{"binaryData":{"one":"","three":"3","two":"2"},"data":"","kind":"ConfigMap","name":""}`, ""},
 		// two keys, one string and one binary values
 		{"two keys with one each", `
 apiVersion: v1
@@ -301,7 +317,7 @@ type: my-type
 data:
   two: 2
   one: ""
-  three: 3`, `{"data":{"one":"","three":3,"two":2},"kind":"Secret","name":"","type":"my-type"}`, ""},
+  three: 3`, `{"data":{"one":"","three":"3","two":"2"},"kind":"Secret","name":"","type":"my-type"}`, ""},
 		// with stringdata
 		{"stringdata", `
 apiVersion: v1
@@ -310,7 +326,7 @@ type: my-type
 data:
   one: ""
 stringData:
-  two: 2`, `{"data":{"one":""},"kind":"Secret","name":"","stringData":{"two":2},"type":"my-type"}`, ""},
+  two: 2`, `{"data":{"one":""},"kind":"Secret","name":"","stringData":{"two":"2"},"type":"my-type"}`, ""},
 		// empty stringdata
 		{"empty stringdata", `
 apiVersion: v1
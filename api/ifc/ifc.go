@@ -5,6 +5,8 @@
 package ifc
 
 import (
+	"time"
+
 	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/types"
 )
@@ -38,6 +40,14 @@ type Loader interface {
 	Cleanup() error
 }
 
+// DirLister is implemented by the Loaders that can list
+// the contents of a directory.
+type DirLister interface {
+	// ListDir returns the sorted names of the directories and
+	// of the files in the directory at the location.
+	ListDir(location string) (dirs, files []string, err error)
+}
+
 // Kunstructured represents a Kubernetes Resource Model object.
 type Kunstructured interface {
 	// Several uses.
@@ -128,3 +138,55 @@ type KunstructuredHasher interface {
 
 // See core.v1.SecretTypeOpaque
 const SecretTypeOpaque = "Opaque"
+
+// HelmInflater renders Helm charts, in place of running the
+// helm binary.  Kustomize doesn't bundle one; whoever runs a
+// build may supply it.
+type HelmInflater interface {
+	// Inflate returns the YAML manifests the chart renders to.
+	Inflate(chart types.HelmChartArgs) ([]byte, error)
+}
+
+// OciPuller pulls OCI artifacts holding kustomizations, e.g.
+// from a registry.  Kustomize doesn't bundle one; whoever runs
+// a build of an artifact supplies it.
+type OciPuller interface {
+	// Pull returns the files of the layers of the artifact with
+	// the given reference, by their slash separated paths
+	// relative to the root of the artifact.
+	Pull(ref string) (map[string][]byte, error)
+}
+
+// BuildPhase names a phase of the build of a kustomization.
+type BuildPhase string
+
+const (
+	// PhaseAccumulate reads the resources, bases and components.
+	PhaseAccumulate BuildPhase = "accumulate"
+	// PhaseGenerate runs the generators.
+	PhaseGenerate BuildPhase = "generate"
+	// PhaseTransform runs one transformer.
+	PhaseTransform BuildPhase = "transform"
+)
+
+// PhaseEvent describes a phase of a build, as it starts or ends.
+type PhaseEvent struct {
+	Phase BuildPhase
+	// Root is the root of the kustomization being built, as
+	// every base and component has phases of its own.
+	Root string
+	// Name names the transformer of a PhaseTransform.
+	Name string
+	// Duration is how long the phase took; zero as it starts.
+	Duration time.Duration
+	// Count is the number of resources as the phase ended;
+	// zero as it starts.
+	Count int
+}
+
+// BuildHooks observe the phases of a build, e.g. to time them.
+// They're called from the goroutine running the build.
+type BuildHooks interface {
+	PhaseStart(e PhaseEvent)
+	PhaseEnd(e PhaseEvent)
+}
@@ -4,6 +4,7 @@
 package image
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
@@ -48,3 +49,100 @@ func Split(imageName string) (name string, tag string) {
 	tag = imageName[i:]
 	return
 }
+
+// The registry that image names without one refer to, and the
+// repository namespace of its official images.
+const (
+	defaultRegistry  = "docker.io"
+	officialImageOrg = "library"
+)
+
+// Normalize returns the fully qualified form of the image
+// reference, i.e. with an explicit registry, e.g. "nginx"
+// becomes "docker.io/library/nginx" and "me/app:v1" becomes
+// "docker.io/me/app:v1".  The tag or digest, if any, is kept.
+func Normalize(imageName string) string {
+	name, tag := Split(imageName)
+	i := strings.Index(name, "/")
+	if i < 0 {
+		return defaultRegistry + "/" + officialImageOrg + "/" + name + tag
+	}
+	if isRegistry(name[:i]) {
+		return name + tag
+	}
+	return defaultRegistry + "/" + name + tag
+}
+
+// isRegistry returns true if the first component of an image
+// name is a registry host rather than a repository namespace.
+func isRegistry(s string) bool {
+	return strings.ContainsAny(s, ".:") || s == "localhost"
+}
+
+// IsLatest returns true if the image reference names the
+// latest tag, explicitly or by having neither tag nor digest.
+func IsLatest(imageName string) bool {
+	_, tag := Split(imageName)
+	return tag == "" || tag == ":latest"
+}
+
+var (
+	// A registry host, optionally with a port.
+	registryPattern = regexp.MustCompile(
+		`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?` +
+			`(\.[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?)*(:[0-9]+)?$`)
+	// A component of a repository path.
+	pathComponentPattern = regexp.MustCompile(
+		`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*$`)
+	// Like IsImageMatched, this allows {} for tag patterns.
+	tagPattern = regexp.MustCompile(`^[a-zA-Z0-9_{}][a-zA-Z0-9_.{}-]{0,127}$`)
+	// As per the OCI image spec.
+	digestPattern = regexp.MustCompile(
+		`^[a-z0-9]+([+._-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+)
+
+// ValidateName returns an error if the image name, which
+// must have neither tag nor digest, is malformed.
+func ValidateName(imageName string) error {
+	if imageName == "" {
+		return fmt.Errorf("empty image name")
+	}
+	components := strings.Split(imageName, "/")
+	if len(components) > 1 && isRegistry(components[0]) {
+		if !registryPattern.MatchString(components[0]) {
+			return fmt.Errorf("invalid registry '%s'", components[0])
+		}
+		components = components[1:]
+	}
+	for _, c := range components {
+		if !pathComponentPattern.MatchString(c) {
+			if _, tag := Split(imageName); len(tag) == 1 {
+				return fmt.Errorf("empty tag or digest after '%s'", tag)
+			} else if tag != "" {
+				return fmt.Errorf(
+					"image name must not have a tag or digest, found '%s'", tag)
+			}
+			return fmt.Errorf("invalid path component '%s'", c)
+		}
+	}
+	return nil
+}
+
+// ValidateTag returns an error if the tag, given without
+// its separator, is malformed.
+func ValidateTag(tag string) error {
+	if !tagPattern.MatchString(tag) {
+		return fmt.Errorf("invalid tag '%s'", tag)
+	}
+	return nil
+}
+
+// ValidateDigest returns an error if the digest, e.g.
+// "sha256:" followed by the hex encoded hash, is malformed.
+func ValidateDigest(digest string) error {
+	if !digestPattern.MatchString(digest) {
+		return fmt.Errorf(
+			"invalid digest '%s'; expected <algorithm>:<encoded>", digest)
+	}
+	return nil
+}
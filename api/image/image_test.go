@@ -78,3 +78,103 @@ func TestSplit(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalize(t *testing.T) {
+	testCases := []struct {
+		testName string
+		value    string
+		expected string
+	}{
+		{
+			testName: "official image",
+			value:    "nginx",
+			expected: "docker.io/library/nginx",
+		},
+		{
+			testName: "official image with tag",
+			value:    "nginx:1.2.3",
+			expected: "docker.io/library/nginx:1.2.3",
+		},
+		{
+			testName: "docker hub image with digest",
+			value:    "me/app@sha256:abcd",
+			expected: "docker.io/me/app@sha256:abcd",
+		},
+		{
+			testName: "already qualified",
+			value:    "docker.io/library/nginx",
+			expected: "docker.io/library/nginx",
+		},
+		{
+			testName: "other registry",
+			value:    "gcr.io/project/app:v1",
+			expected: "gcr.io/project/app:v1",
+		},
+		{
+			testName: "registry with port",
+			value:    "registry:5000/app",
+			expected: "registry:5000/app",
+		},
+		{
+			testName: "localhost",
+			value:    "localhost/app",
+			expected: "localhost/app",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Normalize(tc.value))
+		})
+	}
+}
+
+func TestIsLatest(t *testing.T) {
+	assert.True(t, IsLatest("nginx"))
+	assert.True(t, IsLatest("gcr.io/app:latest"))
+	assert.False(t, IsLatest("nginx:1.2.3"))
+	assert.False(t, IsLatest("nginx@sha256:abcd"))
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{
+		"nginx",
+		"me/app",
+		"gcr.io/project/app",
+		"registry:5000/team/app-x_y",
+		"localhost/app",
+	} {
+		assert.NoError(t, ValidateName(name), name)
+	}
+	testCases := map[string]string{
+		"registry:5000/app:": "empty tag or digest after ':'",
+		"nginx:1.21":         "image name must not have a tag or digest, found ':1.21'",
+		"app@sha256:abcd":    "image name must not have a tag or digest, found '@sha256:abcd'",
+		"gcr.io//app":        "invalid path component ''",
+		"Nginx":              "invalid path component 'Nginx'",
+		"-bad.io:x/app":      "invalid registry '-bad.io:x'",
+		"":                   "empty image name",
+	}
+	for name, expected := range testCases {
+		err := ValidateName(name)
+		if assert.Error(t, err, name) {
+			assert.Equal(t, expected, err.Error())
+		}
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	assert.NoError(t, ValidateTag("1.21-alpine"))
+	assert.NoError(t, ValidateTag("{STABLE_TAG}"))
+	assert.Error(t, ValidateTag(""))
+	assert.Error(t, ValidateTag(".hidden"))
+	assert.Error(t, ValidateTag("v1:2"))
+}
+
+func TestValidateDigest(t *testing.T) {
+	assert.NoError(t, ValidateDigest("sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3"))
+	assert.Error(t, ValidateDigest("sha256"))
+	assert.Error(t, ValidateDigest("sha256:"))
+	assert.Error(t, ValidateDigest("SHA256:abcd"))
+	assert.Error(t, ValidateDigest("sha256:ab/cd"))
+}
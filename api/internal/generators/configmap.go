@@ -41,5 +41,8 @@ func MakeConfigMap(
 		return nil, err
 	}
 	copyLabelsAndAnnotations(rn, args.Options)
+	if err = setFinalizers(rn, args.Options); err != nil {
+		return nil, err
+	}
 	return rn, nil
 }
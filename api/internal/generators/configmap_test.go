@@ -186,6 +186,46 @@ data:
 `,
 			},
 		},
+		"construct config map with finalizers": {
+			args: types.ConfigMapArgs{
+				GeneratorArgs: types.GeneratorArgs{
+					Name: "literalConfigMap3",
+					KvPairSources: types.KvPairSources{
+						LiteralSources: []string{"a=x"},
+					},
+					Options: &types.GeneratorOptions{
+						Finalizers: []string{"example.com/cleanup"},
+					},
+				},
+			},
+			exp: expected{
+				out: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: literalConfigMap3
+  finalizers:
+  - example.com/cleanup
+data:
+  a: x
+`,
+			},
+		},
+		"construct config map with an empty finalizer": {
+			args: types.ConfigMapArgs{
+				GeneratorArgs: types.GeneratorArgs{
+					Name: "literalConfigMap4",
+					KvPairSources: types.KvPairSources{
+						LiteralSources: []string{"a=x"},
+					},
+					Options: &types.GeneratorOptions{
+						Finalizers: []string{""},
+					},
+				},
+			},
+			exp: expected{
+				errMsg: "generator finalizers must not be empty",
+			},
+		},
 	}
 	fSys := filesys.MakeFsInMemory()
 	fSys.WriteFile(
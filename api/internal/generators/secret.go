@@ -54,5 +54,8 @@ func MakeSecret(
 		return nil, err
 	}
 	copyLabelsAndAnnotations(rn, args.Options)
+	if err = setFinalizers(rn, args.Options); err != nil {
+		return nil, err
+	}
 	return rn, nil
 }
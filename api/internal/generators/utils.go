@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-errors/errors"
 	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/konfig"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/yaml"
 )
@@ -56,7 +57,8 @@ func makeValidatedDataMap(
 }
 
 // copyLabelsAndAnnotations copies labels and annotations from
-// GeneratorOptions into the given object.
+// GeneratorOptions, including that of the field manager, into
+// the given object.
 func copyLabelsAndAnnotations(
 	rn *yaml.RNode, opts *types.GeneratorOptions) error {
 	if opts == nil {
@@ -74,5 +76,28 @@ func copyLabelsAndAnnotations(
 			return err
 		}
 	}
+	if opts.FieldManager != "" {
+		_, err := rn.Pipe(yaml.SetAnnotation(
+			konfig.FieldManagerAnnotation, opts.FieldManager))
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// setFinalizers sets the finalizers of GeneratorOptions, if any,
+// on the given object.
+func setFinalizers(rn *yaml.RNode, opts *types.GeneratorOptions) error {
+	if opts == nil || len(opts.Finalizers) == 0 {
+		return nil
+	}
+	for _, f := range opts.Finalizers {
+		if f == "" {
+			return errors.Errorf("generator finalizers must not be empty")
+		}
+	}
+	return rn.PipeE(
+		yaml.Lookup(yaml.MetadataField),
+		yaml.SetField("finalizers", yaml.NewListRNode(opts.Finalizers...)))
+}
@@ -24,11 +24,28 @@ func _() {
 	_ = x[SecretGenerator-13]
 	_ = x[ValueAddTransformer-14]
 	_ = x[HelmChartInflationGenerator-15]
+	_ = x[MetadataTemplateTransformer-16]
+	_ = x[ServiceMonitorTransformer-17]
+	_ = x[JsonArrayGenerator-18]
+	_ = x[SecurityContextTransformer-19]
+	_ = x[ImageNormalizationTransformer-20]
+	_ = x[DefaultDenyPolicyTransformer-21]
+	_ = x[RecommendedLabelsTransformer-22]
+	_ = x[PriorityClassTransformer-23]
+	_ = x[DefaultResourceQuotaTransformer-24]
+	_ = x[NamespaceRelocationTransformer-25]
+	_ = x[DefaultServiceAccountTransformer-26]
+	_ = x[ExternalSecretGenerator-27]
+	_ = x[GoTemplateGenerator-28]
+	_ = x[NodePlacementTransformer-29]
+	_ = x[ContainerProbeTransformer-30]
+	_ = x[ConfigMapRolloutTransformer-31]
+	_ = x[ContainerResourcesTransformer-32]
 }
 
-const _BuiltinPluginType_name = "UnknownAnnotationsTransformerConfigMapGeneratorHashTransformerImageTagTransformerLabelTransformerLegacyOrderTransformerNamespaceTransformerPatchJson6902TransformerPatchStrategicMergeTransformerPatchTransformerPrefixSuffixTransformerReplicaCountTransformerSecretGeneratorValueAddTransformerHelmChartInflationGenerator"
+const _BuiltinPluginType_name = "UnknownAnnotationsTransformerConfigMapGeneratorHashTransformerImageTagTransformerLabelTransformerLegacyOrderTransformerNamespaceTransformerPatchJson6902TransformerPatchStrategicMergeTransformerPatchTransformerPrefixSuffixTransformerReplicaCountTransformerSecretGeneratorValueAddTransformerHelmChartInflationGeneratorMetadataTemplateTransformerServiceMonitorTransformerJsonArrayGeneratorSecurityContextTransformerImageNormalizationTransformerDefaultDenyPolicyTransformerRecommendedLabelsTransformerPriorityClassTransformerDefaultResourceQuotaTransformerNamespaceRelocationTransformerDefaultServiceAccountTransformerExternalSecretGeneratorGoTemplateGeneratorNodePlacementTransformerContainerProbeTransformerConfigMapRolloutTransformerContainerResourcesTransformer"
 
-var _BuiltinPluginType_index = [...]uint16{0, 7, 29, 47, 62, 81, 97, 119, 139, 163, 193, 209, 232, 255, 270, 289, 316}
+var _BuiltinPluginType_index = [...]uint16{0, 7, 29, 47, 62, 81, 97, 119, 139, 163, 193, 209, 232, 255, 270, 289, 316, 343, 368, 386, 412, 441, 469, 497, 521, 552, 582, 614, 637, 656, 680, 705, 732, 761}
 
 func (i BuiltinPluginType) String() string {
 	if i < 0 || i >= BuiltinPluginType(len(_BuiltinPluginType_index)-1) {
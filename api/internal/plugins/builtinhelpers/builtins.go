@@ -28,6 +28,23 @@ const (
 	SecretGenerator
 	ValueAddTransformer
 	HelmChartInflationGenerator
+	MetadataTemplateTransformer
+	ServiceMonitorTransformer
+	JsonArrayGenerator
+	SecurityContextTransformer
+	ImageNormalizationTransformer
+	DefaultDenyPolicyTransformer
+	RecommendedLabelsTransformer
+	PriorityClassTransformer
+	DefaultResourceQuotaTransformer
+	NamespaceRelocationTransformer
+	DefaultServiceAccountTransformer
+	ExternalSecretGenerator
+	GoTemplateGenerator
+	NodePlacementTransformer
+	ContainerProbeTransformer
+	ConfigMapRolloutTransformer
+	ContainerResourcesTransformer
 )
 
 var stringToBuiltinPluginTypeMap map[string]BuiltinPluginType
@@ -59,19 +76,36 @@ var GeneratorFactories = map[BuiltinPluginType]func() resmap.GeneratorPlugin{
 	ConfigMapGenerator:          builtins.NewConfigMapGeneratorPlugin,
 	SecretGenerator:             builtins.NewSecretGeneratorPlugin,
 	HelmChartInflationGenerator: builtins.NewHelmChartInflationGeneratorPlugin,
+	JsonArrayGenerator:          builtins.NewJsonArrayGeneratorPlugin,
+	ExternalSecretGenerator:     builtins.NewExternalSecretGeneratorPlugin,
+	GoTemplateGenerator:         builtins.NewGoTemplateGeneratorPlugin,
 }
 
 var TransformerFactories = map[BuiltinPluginType]func() resmap.TransformerPlugin{
-	AnnotationsTransformer:         builtins.NewAnnotationsTransformerPlugin,
-	HashTransformer:                builtins.NewHashTransformerPlugin,
-	ImageTagTransformer:            builtins.NewImageTagTransformerPlugin,
-	LabelTransformer:               builtins.NewLabelTransformerPlugin,
-	LegacyOrderTransformer:         builtins.NewLegacyOrderTransformerPlugin,
-	NamespaceTransformer:           builtins.NewNamespaceTransformerPlugin,
-	PatchJson6902Transformer:       builtins.NewPatchJson6902TransformerPlugin,
-	PatchStrategicMergeTransformer: builtins.NewPatchStrategicMergeTransformerPlugin,
-	PatchTransformer:               builtins.NewPatchTransformerPlugin,
-	PrefixSuffixTransformer:        builtins.NewPrefixSuffixTransformerPlugin,
-	ReplicaCountTransformer:        builtins.NewReplicaCountTransformerPlugin,
-	ValueAddTransformer:            builtins.NewValueAddTransformerPlugin,
+	AnnotationsTransformer:           builtins.NewAnnotationsTransformerPlugin,
+	HashTransformer:                  builtins.NewHashTransformerPlugin,
+	ImageTagTransformer:              builtins.NewImageTagTransformerPlugin,
+	LabelTransformer:                 builtins.NewLabelTransformerPlugin,
+	LegacyOrderTransformer:           builtins.NewLegacyOrderTransformerPlugin,
+	NamespaceTransformer:             builtins.NewNamespaceTransformerPlugin,
+	PatchJson6902Transformer:         builtins.NewPatchJson6902TransformerPlugin,
+	PatchStrategicMergeTransformer:   builtins.NewPatchStrategicMergeTransformerPlugin,
+	PatchTransformer:                 builtins.NewPatchTransformerPlugin,
+	PrefixSuffixTransformer:          builtins.NewPrefixSuffixTransformerPlugin,
+	ReplicaCountTransformer:          builtins.NewReplicaCountTransformerPlugin,
+	ValueAddTransformer:              builtins.NewValueAddTransformerPlugin,
+	MetadataTemplateTransformer:      builtins.NewMetadataTemplateTransformerPlugin,
+	ServiceMonitorTransformer:        builtins.NewServiceMonitorTransformerPlugin,
+	SecurityContextTransformer:       builtins.NewSecurityContextTransformerPlugin,
+	ImageNormalizationTransformer:    builtins.NewImageNormalizationTransformerPlugin,
+	DefaultDenyPolicyTransformer:     builtins.NewDefaultDenyPolicyTransformerPlugin,
+	RecommendedLabelsTransformer:     builtins.NewRecommendedLabelsTransformerPlugin,
+	PriorityClassTransformer:         builtins.NewPriorityClassTransformerPlugin,
+	DefaultResourceQuotaTransformer:  builtins.NewDefaultResourceQuotaTransformerPlugin,
+	NamespaceRelocationTransformer:   builtins.NewNamespaceRelocationTransformerPlugin,
+	DefaultServiceAccountTransformer: builtins.NewDefaultServiceAccountTransformerPlugin,
+	NodePlacementTransformer:         builtins.NewNodePlacementTransformerPlugin,
+	ContainerProbeTransformer:        builtins.NewContainerProbeTransformerPlugin,
+	ConfigMapRolloutTransformer:      builtins.NewConfigMapRolloutTransformerPlugin,
+	ContainerResourcesTransformer:    builtins.NewContainerResourcesTransformerPlugin,
 }
@@ -11,6 +11,7 @@ import (
 	"plugin"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/ifc"
@@ -29,6 +30,8 @@ import (
 type Loader struct {
 	pc *types.PluginConfig
 	rf *resmap.Factory
+	// If not nil, what the plugins see as the current time.
+	now func() time.Time
 }
 
 func NewLoader(
@@ -36,6 +39,14 @@ func NewLoader(
 	return &Loader{pc: pc, rf: rf}
 }
 
+// WithNow returns a copy of the loader whose plugins see the
+// time f returns as the current time; see PluginHelpers.Now.
+func (l *Loader) WithNow(f func() time.Time) *Loader {
+	c := *l
+	c.now = f
+	return &c
+}
+
 func (l *Loader) LoadGenerators(
 	ldr ifc.Loader, v ifc.Validator, rm resmap.ResMap) ([]resmap.Generator, error) {
 	var result []resmap.Generator
@@ -148,7 +159,7 @@ func (l *Loader) loadAndConfigurePlugin(
 	if err != nil {
 		return nil, errors.Wrapf(err, "marshalling yaml from res %s", res.OrgId())
 	}
-	err = c.Config(resmap.NewPluginHelpers(ldr, v, l.rf), yaml)
+	err = c.Config(resmap.NewPluginHelpers(ldr, v, l.rf).WithNow(l.now), yaml)
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "plugin %s fails configuration", res.OrgId())
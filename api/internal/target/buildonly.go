@@ -0,0 +1,159 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"log"
+
+	"sigs.k8s.io/kustomize/api/filters/fieldspec"
+	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// dropBuildOnly removes from the map the resources carrying the
+// given annotation, unless its value is "false", and removes the
+// annotation from the resources that remain.  Resources that
+// remain may still refer to removed ones; as that's likely a
+// mistake, it's logged.
+func dropBuildOnly(
+	m resmap.ResMap, key string, tConfig *builtinconfig.TransformerConfig) error {
+	var dropped, kept []*resource.Resource
+	for _, r := range m.Resources() {
+		annotations := r.GetAnnotations()
+		v, ok := annotations[key]
+		if !ok {
+			kept = append(kept, r)
+			continue
+		}
+		if v == "false" {
+			delete(annotations, key)
+			r.SetAnnotations(annotations)
+			kept = append(kept, r)
+			continue
+		}
+		dropped = append(dropped, r)
+	}
+	for _, r := range dropped {
+		referrers, err := findReferrers(r, kept, tConfig.NameReference)
+		if err != nil {
+			return err
+		}
+		for _, id := range referrers {
+			log.Printf(
+				"dropping build-only resource %s still referred to by %s",
+				r.CurId(), id)
+		}
+		if err = m.Remove(r.CurId()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findReferrers returns the ids of the candidates holding the name
+// of the given resource in a field that, per the name references,
+// may refer to a resource of its kind.
+func findReferrers(
+	r *resource.Resource, candidates []*resource.Resource,
+	backRefs []builtinconfig.NameBackReferences) ([]resid.ResId, error) {
+	refs, err := findReferences(r, candidates, backRefs)
+	if err != nil {
+		return nil, err
+	}
+	var result []resid.ResId
+	for i, ref := range refs {
+		if i == 0 || refs[i-1].from != ref.from {
+			result = append(result, ref.from.CurId())
+		}
+	}
+	return result, nil
+}
+
+// reference is a field of one resource naming another.
+type reference struct {
+	from  *resource.Resource
+	field string
+}
+
+// findReferences returns the fields of the candidates that hold
+// the name of the given resource and that, per the name references,
+// may refer to a resource of its kind, in candidate order.
+// Candidates in other namespaces than a namespaced resource
+// are assumed to mean some other resource of the same name.
+func findReferences(
+	r *resource.Resource, candidates []*resource.Resource,
+	backRefs []builtinconfig.NameBackReferences) ([]reference, error) {
+	var result []reference
+	for _, c := range candidates {
+		if c == r || (isNamespaced(r) && isNamespaced(c) &&
+			!c.CurId().IsNsEquals(r.CurId())) {
+			continue
+		}
+		for _, br := range backRefs {
+			if !r.OrgId().IsSelected(&br.Gvk) {
+				continue
+			}
+			for _, fs := range br.Referrers {
+				if !c.OrgId().IsSelected(&fs.Gvk) {
+					continue
+				}
+				found := false
+				err := c.ApplyFilter(kio.FilterAll(fieldspec.Filter{
+					FieldSpec: fs,
+					SetValue: func(node *yaml.RNode) error {
+						found = found || holdsName(node, r.GetName())
+						return nil
+					},
+				}))
+				if err != nil {
+					return nil, err
+				}
+				if found && !hasReference(result, c, fs.Path) {
+					result = append(result, reference{from: c, field: fs.Path})
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func hasReference(refs []reference, from *resource.Resource, field string) bool {
+	for _, ref := range refs {
+		if ref.from == from && ref.field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func isNamespaced(r *resource.Resource) bool {
+	return r.GetGvk().IsNamespaceableKind()
+}
+
+// holdsName returns true if the node is the name, or is a map
+// with the name in its name field, or a list of either.
+func holdsName(node *yaml.RNode, name string) bool {
+	switch node.YNode().Kind {
+	case yaml.ScalarNode:
+		return node.YNode().Value == name
+	case yaml.MappingNode:
+		n := node.Field("name")
+		return n != nil && holdsName(n.Value, name)
+	case yaml.SequenceNode:
+		elements, err := node.Elements()
+		if err != nil {
+			return false
+		}
+		for _, e := range elements {
+			if holdsName(e, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"time"
+
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// BuildOptions holds settings that apply to an entire build,
+// i.e. to the root kustomization and to every base and
+// component reached from it.  Unlike kustomization file
+// fields, these are chosen by the caller at build time.
+type BuildOptions struct {
+	// When true, no builtin generator adds a content hash
+	// suffix to the names of the objects it makes, overriding
+	// the generatorOptions of every kustomization file.
+	DisableNameSuffixHash bool
+
+	// Profile names the build profile, e.g. "prod", that picks
+	// which profile-specific kustomization entries apply.
+	// Empty means only entries that belong to no profile apply.
+	Profile string
+
+	// When true, the build notes which transformers change
+	// each resource; see BuildMetadata.
+	RecordTransformations bool
+
+	// When true, the build notes which resources of its output
+	// refer to which others; see BuildMetadata.
+	RecordDependencies bool
+
+	// When true, the build notes which fields each patch
+	// changes; see BuildMetadata.
+	RecordPatchChanges bool
+
+	// When true, every resource of the output is annotated with
+	// the bases it came through and the transformers that
+	// changed it; see konfig.ProvenanceAnnotation.
+	RecordProvenance bool
+
+	// BuildOnlyAnnotation, if not empty, is an annotation key
+	// marking resources that only exist to help the build, e.g.
+	// as the source of vars, and so are left out of the output.
+	// A value of "false" keeps the resource.  Either way the
+	// annotation itself isn't emitted.
+	BuildOnlyAnnotation string
+
+	// When true, image entries may take their new tag from
+	// an environment variable named by newTagEnv.
+	ImageTagsFromEnv bool
+
+	// BuildTime, if not zero, is the time used in place of the
+	// current time wherever the build stamps a time, making
+	// the output reproducible.
+	BuildTime time.Time
+
+	// When true, patches warn about targets they leave
+	// unchanged.
+	WarnOnNoOpPatches bool
+
+	// When true, generated objects of different content whose
+	// hash suffixes happen to give them the same name get longer
+	// suffixes until their names differ; otherwise the build
+	// fails.
+	LengthenHashOnCollision bool
+
+	// When true, the build fails if the image of any container
+	// in its output isn't pinned to a sha256 digest.
+	RequireImageDigests bool
+
+	// When true, the env vars of every container in the output
+	// are sorted by name, short of changing their values.
+	SortContainerEnv bool
+
+	// AllowedPlugins, if not nil, names the only builtin
+	// plugins, e.g. "ConfigMapGenerator", that the build may
+	// run, whether configured by kustomization fields or by
+	// configs in the generators or transformers fields.  The
+	// build fails before running any plugin if it needs others.
+	AllowedPlugins []string
+
+	// MaxBaseDepth, if not zero, is how deep bases and components
+	// may be included, the bases of the root kustomization being
+	// at depth one; zero means defaultMaxBaseDepth.  The build
+	// fails at the first inclusion deeper than that.
+	MaxBaseDepth int
+}
+
+// The depth of base inclusion allowed by default, deep enough
+// for any sane composition of bases and components.
+const defaultMaxBaseDepth = 100
+
+// maxBaseDepth returns how deep bases and components may be
+// included.
+func (kt *KustTarget) maxBaseDepth() int {
+	if kt.buildOptions.MaxBaseDepth > 0 {
+		return kt.buildOptions.MaxBaseDepth
+	}
+	return defaultMaxBaseDepth
+}
+
+// SetBuildOptions replaces the build options of the target.
+func (kt *KustTarget) SetBuildOptions(o BuildOptions) {
+	kt.buildOptions = o
+	kt.recorder = nil
+	if o.RecordTransformations || o.RecordProvenance {
+		kt.recorder = newTransformationRecorder(o.RecordProvenance)
+	}
+	kt.patchReports = nil
+	if o.RecordPatchChanges {
+		kt.patchReports = &[]types.PatchReport{}
+	}
+}
+
+// now returns the time the build stamps into its output.
+func (kt *KustTarget) now() time.Time {
+	if !kt.buildOptions.BuildTime.IsZero() {
+		return kt.buildOptions.BuildTime
+	}
+	return time.Now()
+}
@@ -0,0 +1,21 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildTime(t *testing.T) {
+	kt := &KustTarget{}
+	if kt.now().IsZero() {
+		t.Fatalf("expected the current time")
+	}
+	fixed := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	kt.SetBuildOptions(BuildOptions{BuildTime: fixed})
+	if got := kt.now(); !got.Equal(fixed) {
+		t.Fatalf("expected %v, got %v", fixed, got)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"regexp"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Matches a reference, e.g. $(HOST), in the value of an env var
+// to another env var of its container.
+var envVarRef = regexp.MustCompile(`\$\(([-._a-zA-Z][-._a-zA-Z0-9]*)\)`)
+
+// sortContainerEnv sorts the env vars of the containers of the
+// resources by name.  A var referring to another keeps its place
+// before or after that one, as only the vars before a var expand
+// in its value.  A container with two vars of the same name is
+// left as is, as the last of them wins.
+func sortContainerEnv(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		err := r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				sortEnvIn(node)
+				return node, nil
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortEnvIn sorts the env vars of the containers in all the
+// container lists in the node.
+func sortEnvIn(node *yaml.RNode) {
+	n := node.YNode()
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(n.Content); i += 2 {
+			key, value := n.Content[i].Value, n.Content[i+1]
+			if containerFields[key] && value.Kind == yaml.SequenceNode {
+				for _, c := range value.Content {
+					env := yaml.NewRNode(c).Field("env")
+					if env != nil && env.Value.YNode().Kind == yaml.SequenceNode {
+						sortEnv(env.Value.YNode())
+					}
+				}
+				continue
+			}
+			sortEnvIn(yaml.NewRNode(value))
+		}
+	case yaml.SequenceNode:
+		for _, e := range n.Content {
+			sortEnvIn(yaml.NewRNode(e))
+		}
+	}
+}
+
+// sortEnv sorts the vars of the env list by name, except that
+// of two vars one of which refers to the other, the one that
+// came first stays first.
+func sortEnv(env *yaml.Node) {
+	vars := env.Content
+	names := make([]string, len(vars))
+	index := make(map[string]int)
+	for i, v := range vars {
+		name := yaml.NewRNode(v).Field("name")
+		if name == nil || name.Value.YNode().Kind != yaml.ScalarNode {
+			return
+		}
+		names[i] = name.Value.YNode().Value
+		if _, found := index[names[i]]; found {
+			return
+		}
+		index[names[i]] = i
+	}
+	// before[j] lists the vars that must precede var j.
+	before := make([][]int, len(vars))
+	for i, v := range vars {
+		value := yaml.NewRNode(v).Field("value")
+		if value == nil || value.Value.YNode().Kind != yaml.ScalarNode {
+			continue
+		}
+		for _, ref := range envVarRef.FindAllStringSubmatch(
+			value.Value.YNode().Value, -1) {
+			j, found := index[ref[1]]
+			switch {
+			case !found || j == i:
+			case j < i:
+				before[i] = append(before[i], j)
+			default:
+				before[j] = append(before[j], i)
+			}
+		}
+	}
+	sorted := make([]*yaml.Node, 0, len(vars))
+	done := make([]bool, len(vars))
+	for len(sorted) < len(vars) {
+		next := -1
+		for i := range vars {
+			if done[i] || !allDone(before[i], done) {
+				continue
+			}
+			if next < 0 || names[i] < names[next] {
+				next = i
+			}
+		}
+		done[next] = true
+		sorted = append(sorted, vars[next])
+	}
+	env.Content = sorted
+}
+
+func allDone(indices []int, done []bool) bool {
+	for _, i := range indices {
+		if !done[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+const ownerReferencesField = "metadata/ownerReferences"
+
+// dependencyGraph returns the references between the resources
+// of the map, both those the name references describe and owner
+// references, ordered by referring resource.
+func dependencyGraph(
+	m resmap.ResMap, tConfig *builtinconfig.TransformerConfig) (
+	[]types.Dependency, error) {
+	resources := m.Resources()
+	index := make(map[*resource.Resource]int, len(resources))
+	for i, r := range resources {
+		index[r] = i
+	}
+	var result []types.Dependency
+	var from []int
+	add := func(r, to *resource.Resource, field string) {
+		result = append(result, types.Dependency{
+			From: r.CurId(), To: to.CurId(), Field: field})
+		from = append(from, index[r])
+	}
+	for _, r := range resources {
+		refs, err := findReferences(r, resources, tConfig.NameReference)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			add(ref.from, r, ref.field)
+		}
+	}
+	for _, r := range resources {
+		for _, owner := range findOwners(r, resources) {
+			add(r, owner, ownerReferencesField)
+		}
+	}
+	sort.Stable(byReferrer{result, from})
+	return result, nil
+}
+
+// findOwners returns the candidates named in the owner
+// references of the given resource.
+func findOwners(
+	r *resource.Resource, candidates []*resource.Resource) []*resource.Resource {
+	refs, err := r.GetSlice("metadata.ownerReferences")
+	if err != nil {
+		return nil
+	}
+	var result []*resource.Resource
+	for _, ref := range refs {
+		owner, ok := ref.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, c := range candidates {
+			if c.GetKind() == owner["kind"] && c.GetName() == owner["name"] &&
+				(!isNamespaced(c) || c.CurId().IsNsEquals(r.CurId())) {
+				result = append(result, c)
+			}
+		}
+	}
+	return result
+}
+
+// byReferrer sorts dependencies by the position of their
+// referring resource in the map.
+type byReferrer struct {
+	deps []types.Dependency
+	from []int
+}
+
+func (b byReferrer) Len() int           { return len(b.deps) }
+func (b byReferrer) Less(i, j int) bool { return b.from[i] < b.from[j] }
+func (b byReferrer) Swap(i, j int) {
+	b.deps[i], b.deps[j] = b.deps[j], b.deps[i]
+	b.from[i], b.from[j] = b.from[j], b.from[i]
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/hasher"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// resolveHashCollisions looks for generated objects that got,
+// from their hash suffixes, the name of another object of
+// different content, which may happen as the suffix holds only
+// part of a hash.  If lengthen is true, such objects get longer
+// suffixes, taken from a hash of their full content, until their
+// names are unique; otherwise that's an error.
+func resolveHashCollisions(m resmap.ResMap, lengthen bool) error {
+	for _, r := range m.Resources() {
+		if !r.NeedHashSuffix() {
+			continue
+		}
+		collides, err := hasCollision(m, r)
+		if err != nil {
+			return err
+		}
+		if !collides {
+			continue
+		}
+		if !lengthen {
+			return fmt.Errorf(
+				"the hash suffix of generated %s gives it the name of "+
+					"another object of different content", r.CurId())
+		}
+		if err = lengthenHashSuffix(m, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasCollision returns true if another resource in the map has
+// the id of the given one, but not its content.
+func hasCollision(m resmap.ResMap, r *resource.Resource) (bool, error) {
+	y, err := r.AsYAML()
+	if err != nil {
+		return false, err
+	}
+	for _, other := range m.GetMatchingResourcesByCurrentId(r.CurId().Equals) {
+		if other == r {
+			continue
+		}
+		o, err := other.AsYAML()
+		if err != nil {
+			return false, err
+		}
+		if string(o) != string(y) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lengthenHashSuffix appends to the name of the resource the
+// shortest prefix of the hash of its content that makes its id
+// unique in the map.
+func lengthenHashSuffix(m resmap.ResMap, r *resource.Resource) error {
+	y, err := r.AsYAML()
+	if err != nil {
+		return err
+	}
+	h := hasher.Hash(string(y))
+	name := r.GetName()
+	for n := 1; n <= len(h); n++ {
+		extra, err := hasher.EncodeN(h, n)
+		if err != nil {
+			return err
+		}
+		r.SetName(name + extra)
+		if len(m.GetMatchingResourcesByCurrentId(r.CurId().Equals)) == 1 {
+			return nil
+		}
+	}
+	r.SetName(name)
+	return fmt.Errorf("cannot make the name of generated %s unique", r.CurId())
+}
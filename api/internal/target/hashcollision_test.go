@@ -0,0 +1,115 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target_test
+
+import (
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+	pLdr "sigs.k8s.io/kustomize/api/internal/plugins/loader"
+	"sigs.k8s.io/kustomize/api/internal/target"
+	"sigs.k8s.io/kustomize/api/konfig"
+	fLdr "sigs.k8s.io/kustomize/api/loader"
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	valtest_test "sigs.k8s.io/kustomize/api/testutils/valtest"
+)
+
+// stubHasher gives every object the same hash.
+type stubHasher struct{}
+
+func (stubHasher) Hash(ifc.Kunstructured) (string, error) {
+	return "hhhhhhhhhh", nil
+}
+
+type stubHasherFactory struct {
+	ifc.KunstructuredFactory
+}
+
+func (stubHasherFactory) Hasher() ifc.KunstructuredHasher {
+	return stubHasher{}
+}
+
+// makeCollidingTarget returns a target that generates a
+// ConfigMap whose hashed name, per the stub hasher, is that of
+// a ConfigMap of different content among its resources.
+func makeCollidingTarget(t *testing.T) *target.KustTarget {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- configmap.yaml
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+`)
+	th.WriteF("/app/configmap.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings-hhhhhhhhhh
+data:
+  mode: slow
+`)
+	ldr, err := fLdr.NewLoader(
+		fLdr.RestrictionRootOnly, "/app", th.GetFSys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pvd := provider.NewDefaultDepProvider()
+	rf := resmap.NewFactory(
+		resource.NewFactory(
+			stubHasherFactory{pvd.GetKunstructuredFactory()}),
+		pvd.GetConflictDetectorFactory())
+	kt := target.NewKustTarget(
+		ldr,
+		valtest_test.MakeFakeValidator(),
+		rf,
+		pLdr.NewLoader(konfig.DisabledPluginConfig(), rf))
+	if err = kt.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return kt
+}
+
+func TestHashCollisionFails(t *testing.T) {
+	kt := makeCollidingTarget(t)
+	_, err := kt.MakeCustomizedResMap()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "gives it the name of another object") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestHashCollisionLengthensSuffix(t *testing.T) {
+	kt := makeCollidingTarget(t)
+	kt.SetBuildOptions(target.BuildOptions{LengthenHashOnCollision: true})
+	m, err := kt.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	names := map[string]bool{}
+	for _, r := range m.Resources() {
+		names[r.GetName()] = true
+	}
+	if len(names) != 2 || !names["settings-hhhhhhhhhh"] {
+		t.Fatalf("unexpected names %v", names)
+	}
+	again := makeCollidingTarget(t)
+	again.SetBuildOptions(target.BuildOptions{LengthenHashOnCollision: true})
+	m2, err := again.MakeCustomizedResMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range m2.Resources() {
+		if !names[r.GetName()] {
+			t.Fatalf("expected the same names in every build, got %s", r.GetName())
+		}
+	}
+}
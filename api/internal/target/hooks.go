@@ -0,0 +1,26 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"time"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+)
+
+// startPhase tells the hooks, if any, that the phase of the
+// event starts, and returns a func to call with the number of
+// resources as it ends.
+func startPhase(hooks ifc.BuildHooks, e ifc.PhaseEvent) func(count int) {
+	if hooks == nil {
+		return func(int) {}
+	}
+	hooks.PhaseStart(e)
+	start := time.Now()
+	return func(count int) {
+		e.Duration = time.Since(start)
+		e.Count = count
+		hooks.PhaseEnd(e)
+	}
+}
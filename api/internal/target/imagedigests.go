@@ -0,0 +1,76 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// Fields holding lists of containers, wherever they appear.
+var containerFields = map[string]bool{
+	"containers":          true,
+	"initContainers":      true,
+	"ephemeralContainers": true,
+}
+
+// errIfImagesLackDigests returns an error listing the images,
+// in the containers of the resources, that aren't pinned to a
+// sha256 digest.
+func errIfImagesLackDigests(m resmap.ResMap) error {
+	var offenders []string
+	for _, r := range m.Resources() {
+		var images []string
+		err := r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				images = containerImages(node)
+				return node, nil
+			})))
+		if err != nil {
+			return err
+		}
+		for _, img := range images {
+			if !strings.Contains(img, "@sha256:") {
+				offenders = append(offenders,
+					fmt.Sprintf("  %s: %s", r.CurId(), img))
+			}
+		}
+	}
+	if len(offenders) == 0 {
+		return nil
+	}
+	return fmt.Errorf("images must be pinned to a sha256 digest:\n%s",
+		strings.Join(offenders, "\n"))
+}
+
+// containerImages returns the images of the containers in
+// all the container lists in the node.
+func containerImages(node *yaml.RNode) (result []string) {
+	n := node.YNode()
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(n.Content); i += 2 {
+			key, value := n.Content[i].Value, n.Content[i+1]
+			if containerFields[key] && value.Kind == yaml.SequenceNode {
+				for _, c := range value.Content {
+					img := yaml.NewRNode(c).Field("image")
+					if img != nil && img.Value.YNode().Kind == yaml.ScalarNode {
+						result = append(result, img.Value.YNode().Value)
+					}
+				}
+				continue
+			}
+			result = append(result, containerImages(yaml.NewRNode(value))...)
+		}
+	case yaml.SequenceNode:
+		for _, e := range n.Content {
+			result = append(result, containerImages(yaml.NewRNode(e))...)
+		}
+	}
+	return result
+}
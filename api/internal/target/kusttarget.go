@@ -6,9 +6,9 @@ package target
 import (
 	"encoding/json"
 	"fmt"
-	"path/filepath"
 	"strings"
 
+	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/builtins"
 	"sigs.k8s.io/kustomize/api/ifc"
@@ -30,6 +30,24 @@ type KustTarget struct {
 	validator     ifc.Validator
 	rFactory      *resmap.Factory
 	pLdr          *loader.Loader
+	buildOptions  BuildOptions
+	helmInflater  ifc.HelmInflater
+	hooks         ifc.BuildHooks
+	recorder      *transformationRecorder
+	buildMetadata types.BuildMetadata
+
+	// Shared, like the recorder, with the targets recursed into.
+	patchReports *[]types.PatchReport
+
+	// The path of the component, as given by the kustomization
+	// including it, if the target is one.
+	component string
+
+	// How deep the target was included, zero for the root.
+	depth int
+
+	// If true, no generators, transformers or validators run.
+	accumulateOnly bool
 }
 
 // NewKustTarget returns a new instance of KustTarget.
@@ -80,6 +98,28 @@ func (kt *KustTarget) Kustomization() types.Kustomization {
 	return result
 }
 
+// OpenAPISchema returns the content of the OpenAPI schema file
+// that the kustomization names, or nil if it names none.
+func (kt *KustTarget) OpenAPISchema() ([]byte, error) {
+	openApiPath, exists := kt.kustomization.OpenAPI["path"]
+	if !exists {
+		return nil, nil
+	}
+	// The loader resolves the path against its own root, which
+	// needn't be a directory, e.g. for an OCI artifact.
+	bytes, err := kt.ldr.Load(openApiPath)
+	if err != nil {
+		return nil, err
+	}
+	// Check it now, as the schema is only parsed when first used.
+	var swagger spec.Swagger
+	if err := swagger.UnmarshalJSON(bytes); err != nil {
+		return nil, errors.Wrapf(err, "malformed OpenAPI schema '%s' in '%s'",
+			openApiPath, kt.ldr.Root())
+	}
+	return bytes, nil
+}
+
 func loadKustFile(ldr ifc.Loader) ([]byte, error) {
 	var content []byte
 	match := 0
@@ -104,14 +144,48 @@ func loadKustFile(ldr ifc.Loader) ([]byte, error) {
 // MakeCustomizedResMap creates a fully customized ResMap
 // per the instructions contained in its kustomization instance.
 func (kt *KustTarget) MakeCustomizedResMap() (resmap.ResMap, error) {
-	return kt.makeCustomizedResMap()
+	return kt.makeCustomizedResMap(accumulator.MakeEmptyAccumulator())
+}
+
+// MakeCustomizedResMapFrom is like MakeCustomizedResMap, but starts
+// from a copy of the given resources, as if they came from a base
+// of the kustomization.  So the kustomization's generators and
+// transformers apply to them, too.  As with bases, the kustomization
+// may not have resources of the same ids, while its generators
+// may merge into or replace them per their behavior.
+func (kt *KustTarget) MakeCustomizedResMapFrom(
+	base resmap.ResMap) (resmap.ResMap, error) {
+	ra := accumulator.MakeEmptyAccumulator()
+	if err := ra.AppendAll(base.DeepCopy()); err != nil {
+		return nil, err
+	}
+	return kt.makeCustomizedResMap(ra)
 }
 
-func (kt *KustTarget) makeCustomizedResMap() (resmap.ResMap, error) {
+// MakeAccumulatedResMap returns the resources of the target
+// and of its bases and components as they are read, i.e.
+// without running generators or transformers in any of them.
+func (kt *KustTarget) MakeAccumulatedResMap() (resmap.ResMap, error) {
+	kt.accumulateOnly = true
+	defer func() { kt.accumulateOnly = false }()
 	ra, err := kt.AccumulateTarget()
 	if err != nil {
 		return nil, err
 	}
+	return ra.ResMap(), nil
+}
+
+func (kt *KustTarget) makeCustomizedResMap(
+	ra *accumulator.ResAccumulator) (resmap.ResMap, error) {
+	// Forget what an earlier build of this target gathered.
+	kt.buildMetadata = types.BuildMetadata{}
+	if kt.patchReports != nil {
+		*kt.patchReports = nil
+	}
+	ra, err := kt.accumulateTarget(ra)
+	if err != nil {
+		return nil, err
+	}
 
 	// The following steps must be done last, not as part of
 	// the recursion implicit in AccumulateTarget.
@@ -134,17 +208,65 @@ func (kt *KustTarget) makeCustomizedResMap() (resmap.ResMap, error) {
 		return nil, err
 	}
 
-	return ra.ResMap(), nil
+	m := ra.ResMap()
+	if kt.buildOptions.BuildOnlyAnnotation != "" {
+		err = dropBuildOnly(
+			m, kt.buildOptions.BuildOnlyAnnotation, ra.GetTransformerConfig())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if kt.buildOptions.RequireImageDigests {
+		if err = errIfImagesLackDigests(m); err != nil {
+			return nil, err
+		}
+	}
+	if kt.buildOptions.SortContainerEnv {
+		if err = sortContainerEnv(m); err != nil {
+			return nil, err
+		}
+	}
+
+	if kt.buildOptions.RecordTransformations {
+		kt.buildMetadata.Transformations = kt.recorder.summarize(m)
+	}
+	if kt.buildOptions.RecordProvenance {
+		if err = annotateProvenance(m); err != nil {
+			return nil, err
+		}
+	}
+	if kt.patchReports != nil {
+		kt.buildMetadata.Patches = *kt.patchReports
+	}
+	if kt.buildOptions.RecordDependencies {
+		kt.buildMetadata.Dependencies, err = dependencyGraph(
+			m, ra.GetTransformerConfig())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// BuildMetadata returns information gathered during the most
+// recent call to MakeCustomizedResMap, per the build options.
+func (kt *KustTarget) BuildMetadata() types.BuildMetadata {
+	return kt.buildMetadata
 }
 
 func (kt *KustTarget) addHashesToNames(
 	ra *accumulator.ResAccumulator) error {
 	p := builtins.NewHashTransformerPlugin()
-	err := kt.configureBuiltinPlugin(p, nil, builtinhelpers.HashTransformer)
+	err := kt.configurePlugin(p, nil, builtinhelpers.HashTransformer)
+	if err != nil {
+		return err
+	}
+	err = ra.Transform(p)
 	if err != nil {
 		return err
 	}
-	return ra.Transform(p)
+	return resolveHashCollisions(
+		ra.ResMap(), kt.buildOptions.LengthenHashOnCollision)
 }
 
 // AccumulateTarget returns a new ResAccumulator,
@@ -160,6 +282,8 @@ func (kt *KustTarget) AccumulateTarget() (
 // (or empty if the Component does not have a parent).
 func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 	resRa *accumulator.ResAccumulator, err error) {
+	end := startPhase(kt.hooks, ifc.PhaseEvent{
+		Phase: ifc.PhaseAccumulate, Root: kt.ldr.Root()})
 	ra, err = kt.accumulateResources(ra, kt.kustomization.Resources)
 	if err != nil {
 		return nil, errors.Wrap(err, "accumulating resources")
@@ -168,6 +292,11 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 	if err != nil {
 		return nil, errors.Wrap(err, "accumulating components")
 	}
+	err = kt.accumulateHelmCharts(ra)
+	if err != nil {
+		return nil, err
+	}
+	end(ra.ResMap().Size())
 	tConfig, err := builtinconfig.MakeTransformerConfig(
 		kt.ldr, kt.kustomization.Configurations)
 	if err != nil {
@@ -188,6 +317,9 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 		return nil, errors.Wrapf(
 			err, "merging CRDs %v", crdTc)
 	}
+	if kt.accumulateOnly {
+		return ra, nil
+	}
 	err = kt.runGenerators(ra)
 	if err != nil {
 		return nil, err
@@ -208,6 +340,53 @@ func (kt *KustTarget) accumulateTarget(ra *accumulator.ResAccumulator) (
 	return ra, nil
 }
 
+// SetBuildHooks sets what observes the phases of the build;
+// nil, the default, leaves them unobserved.
+func (kt *KustTarget) SetBuildHooks(h ifc.BuildHooks) {
+	kt.hooks = h
+}
+
+// SetHelmInflater sets what renders the helm charts named in
+// kustomization files.
+func (kt *KustTarget) SetHelmInflater(h ifc.HelmInflater) {
+	kt.helmInflater = h
+}
+
+// accumulateHelmCharts renders the target's helm charts with the
+// helm inflater, if there is one, into resources, so that they get
+// transformed like any others.  Without an inflater, the charts are
+// left to the HelmChartInflationGenerator.
+func (kt *KustTarget) accumulateHelmCharts(
+	ra *accumulator.ResAccumulator) error {
+	if kt.helmInflater == nil ||
+		len(kt.kustomization.HelmChartInflationGenerator) == 0 {
+		return nil
+	}
+	err := kt.errIfPluginDisallowed(
+		builtinhelpers.HelmChartInflationGenerator.String())
+	if err != nil {
+		return err
+	}
+	for _, chart := range kt.kustomization.HelmChartInflationGenerator {
+		content, err := kt.helmInflater.Inflate(chart)
+		if err != nil {
+			return errors.Wrapf(
+				err, "inflating helm chart '%s'", chart.ChartName)
+		}
+		m, err := kt.rFactory.NewResMapFromBytes(content)
+		if err != nil {
+			return errors.Wrapf(
+				err, "reading output of helm chart '%s'", chart.ChartName)
+		}
+		err = ra.AppendAll(m)
+		if err != nil {
+			return errors.Wrapf(
+				err, "merging output of helm chart '%s'", chart.ChartName)
+		}
+	}
+	return nil
+}
+
 func (kt *KustTarget) runGenerators(
 	ra *accumulator.ResAccumulator) error {
 	var generators []resmap.Generator
@@ -221,6 +400,8 @@ func (kt *KustTarget) runGenerators(
 		return errors.Wrap(err, "loading generator plugins")
 	}
 	generators = append(generators, gs...)
+	end := startPhase(kt.hooks, ifc.PhaseEvent{
+		Phase: ifc.PhaseGenerate, Root: kt.ldr.Root()})
 	for _, g := range generators {
 		resMap, err := g.Generate()
 		if err != nil {
@@ -231,6 +412,7 @@ func (kt *KustTarget) runGenerators(
 			return errors.Wrapf(err, "merging from generator %v", g)
 		}
 	}
+	end(ra.ResMap().Size())
 	return nil
 }
 
@@ -251,7 +433,10 @@ func (kt *KustTarget) configureExternalGenerators() ([]resmap.Generator, error)
 	if err != nil {
 		return nil, err
 	}
-	return kt.pLdr.LoadGenerators(kt.ldr, kt.validator, ra.ResMap())
+	if err = kt.errIfConfigsDisallowed(ra.ResMap()); err != nil {
+		return nil, err
+	}
+	return kt.pLdr.WithNow(kt.now).LoadGenerators(kt.ldr, kt.validator, ra.ResMap())
 }
 
 func (kt *KustTarget) runTransformers(ra *accumulator.ResAccumulator) error {
@@ -267,7 +452,52 @@ func (kt *KustTarget) runTransformers(ra *accumulator.ResAccumulator) error {
 		return err
 	}
 	r = append(r, lts...)
-	return ra.Transform(newMultiTransformer(r))
+	err = ra.Transform(&multiTransformer{
+		transformers: r, recorder: kt.recorder,
+		hooks: kt.hooks, root: kt.ldr.Root(), component: kt.component})
+	if err != nil {
+		return err
+	}
+	if kt.patchReports != nil {
+		for _, t := range r {
+			if o, ok := t.(*originScopedTransformer); ok {
+				t = o.transformer
+			}
+			if pr, ok := t.(resmap.PatchReporter); ok {
+				*kt.patchReports = append(*kt.patchReports, pr.PatchReports()...)
+			}
+		}
+	}
+	kt.restoreGeneratorLabels(ra.ResMap())
+	// Patches can leave pod specs in a state the API server
+	// rejects; better to report that here.
+	return errIfDuplicateContainerNames(ra.ResMap())
+}
+
+// restoreGeneratorLabels gives the generated resources that
+// inherit commonLabels back the values of their own labels that
+// commonLabels overwrote, as the generator labels win.
+func (kt *KustTarget) restoreGeneratorLabels(m resmap.ResMap) {
+	for _, r := range m.Resources() {
+		generated, inherit := r.GeneratorLabels()
+		if !inherit {
+			continue
+		}
+		labels := r.GetLabels()
+		changed := false
+		for k, v := range generated {
+			if _, ok := kt.kustomization.CommonLabels[k]; ok && labels[k] != v {
+				if labels == nil {
+					labels = make(map[string]string)
+				}
+				labels[k] = v
+				changed = true
+			}
+		}
+		if changed {
+			r.SetLabels(labels)
+		}
+	}
 }
 
 func (kt *KustTarget) configureExternalTransformers(transformers []string) ([]resmap.Transformer, error) {
@@ -288,7 +518,20 @@ func (kt *KustTarget) configureExternalTransformers(transformers []string) ([]re
 	if err != nil {
 		return nil, err
 	}
-	return kt.pLdr.LoadTransformers(kt.ldr, kt.validator, ra.ResMap())
+	if err = kt.errIfConfigsDisallowed(ra.ResMap()); err != nil {
+		return nil, err
+	}
+	result, err := kt.pLdr.WithNow(kt.now).LoadTransformers(kt.ldr, kt.validator, ra.ResMap())
+	if err != nil {
+		return nil, err
+	}
+	for i, res := range ra.ResMap().Resources() {
+		if base, ok := res.GetAnnotations()[konfig.OriginBaseAnnotation]; ok {
+			result[i] = &originScopedTransformer{
+				transformer: result[i], base: cleanOrigin(base)}
+		}
+	}
+	return result, nil
 }
 
 func (kt *KustTarget) runValidators(ra *accumulator.ResAccumulator) error {
@@ -340,7 +583,7 @@ func (kt *KustTarget) accumulateResources(
 				return nil, errors.Wrapf(
 					err, "accumulation err='%s'", errF.Error())
 			}
-			ra, err = kt.accumulateDirectory(ra, ldr, false)
+			ra, err = kt.accumulateDirectory(ra, ldr, path, false)
 			if err != nil {
 				return nil, errors.Wrapf(
 					err, "accumulation err='%s'", errF.Error())
@@ -361,7 +604,7 @@ func (kt *KustTarget) accumulateComponents(
 			return nil, fmt.Errorf("loader.New %q", errL)
 		}
 		var errD error
-		ra, errD = kt.accumulateDirectory(ra, ldr, true)
+		ra, errD = kt.accumulateDirectory(ra, ldr, path, true)
 		if errD != nil {
 			return nil, fmt.Errorf("accumulateDirectory: %q", errD)
 		}
@@ -370,21 +613,33 @@ func (kt *KustTarget) accumulateComponents(
 }
 
 func (kt *KustTarget) accumulateDirectory(
-	ra *accumulator.ResAccumulator, ldr ifc.Loader, isComponent bool) (*accumulator.ResAccumulator, error) {
+	ra *accumulator.ResAccumulator, ldr ifc.Loader, path string,
+	isComponent bool) (*accumulator.ResAccumulator, error) {
 	defer ldr.Cleanup()
 	subKt := NewKustTarget(ldr, kt.validator, kt.rFactory, kt.pLdr)
+	subKt.buildOptions = kt.buildOptions
+	subKt.helmInflater = kt.helmInflater
+	subKt.recorder = kt.recorder
+	subKt.patchReports = kt.patchReports
+	subKt.hooks = kt.hooks
+	subKt.accumulateOnly = kt.accumulateOnly
+	if isComponent {
+		subKt.component = cleanOrigin(path)
+	}
+	subKt.depth = kt.depth + 1
+	if subKt.depth > kt.maxBaseDepth() {
+		return nil, fmt.Errorf(
+			"cannot include '%s' from '%s', as bases may only be included %d deep",
+			path, kt.ldr.Root(), kt.maxBaseDepth())
+	}
 	err := subKt.Load()
 	if err != nil {
 		return nil, errors.Wrapf(
 			err, "couldn't make target for path '%s'", ldr.Root())
 	}
-	var bytes []byte
-	path := ldr.Root()
-	if openApiPath, exists := subKt.Kustomization().OpenAPI["path"]; exists {
-		bytes, err = ldr.Load(filepath.Join(path, openApiPath))
-		if err != nil {
-			return nil, err
-		}
+	bytes, err := subKt.OpenAPISchema()
+	if err != nil {
+		return nil, err
 	}
 	err = openapi.SetSchema(subKt.Kustomization().OpenAPI, bytes, false)
 	if err != nil {
@@ -412,6 +667,12 @@ func (kt *KustTarget) accumulateDirectory(
 		return nil, errors.Wrapf(
 			err, "recursed accumulation of path '%s'", ldr.Root())
 	}
+	if !isComponent {
+		setOrigins(subRa.ResMap(), path)
+		if kt.buildOptions.RecordProvenance {
+			addBaseProvenance(subRa.ResMap(), path)
+		}
+	}
 	err = ra.MergeAccumulator(subRa)
 	if err != nil {
 		return nil, errors.Wrapf(
@@ -434,6 +695,17 @@ func (kt *KustTarget) accumulateFile(
 }
 
 func (kt *KustTarget) configureBuiltinPlugin(
+	p resmap.Configurable, c interface{}, bpt builtinhelpers.BuiltinPluginType) (err error) {
+	if err = kt.errIfPluginDisallowed(bpt.String()); err != nil {
+		return err
+	}
+	return kt.configurePlugin(p, c, bpt)
+}
+
+// configurePlugin is configureBuiltinPlugin for plugins that
+// run whether allowed or not, as they only do the plumbing of
+// the build.
+func (kt *KustTarget) configurePlugin(
 	p resmap.Configurable, c interface{}, bpt builtinhelpers.BuiltinPluginType) (err error) {
 	var y []byte
 	if c != nil {
@@ -443,7 +715,8 @@ func (kt *KustTarget) configureBuiltinPlugin(
 				err, "builtin %s marshal", bpt)
 		}
 	}
-	err = p.Config(resmap.NewPluginHelpers(kt.ldr, kt.validator, kt.rFactory), y)
+	err = p.Config(resmap.NewPluginHelpers(
+		kt.ldr, kt.validator, kt.rFactory).WithNow(kt.now), y)
 	if err != nil {
 		return errors.Wrapf(
 			err, "trouble configuring builtin %s with config: `\n%s`", bpt, string(y))
@@ -5,9 +5,13 @@ package target
 
 import (
 	"fmt"
+	"log"
+	"os"
+	"sort"
 
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinhelpers"
+	"sigs.k8s.io/kustomize/api/kv"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
 )
@@ -33,6 +37,9 @@ func (kt *KustTarget) configureBuiltinGenerators() (
 		builtinhelpers.ConfigMapGenerator,
 		builtinhelpers.SecretGenerator,
 		builtinhelpers.HelmChartInflationGenerator,
+		builtinhelpers.JsonArrayGenerator,
+		builtinhelpers.ExternalSecretGenerator,
+		builtinhelpers.GoTemplateGenerator,
 	} {
 		r, err := generatorConfigurators[bpt](
 			kt, bpt, builtinhelpers.GeneratorFactories[bpt])
@@ -53,6 +60,7 @@ func (kt *KustTarget) configureBuiltinTransformers(
 		builtinhelpers.NamespaceTransformer,
 		builtinhelpers.PrefixSuffixTransformer,
 		builtinhelpers.LabelTransformer,
+		builtinhelpers.RecommendedLabelsTransformer,
 		builtinhelpers.AnnotationsTransformer,
 		builtinhelpers.PatchJson6902Transformer,
 		builtinhelpers.ReplicaCountTransformer,
@@ -79,10 +87,20 @@ var generatorConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		var c struct {
 			types.SecretArgs
 		}
+		var all []types.SecretArgs
 		for _, args := range kt.kustomization.SecretGenerator {
+			expanded, err := kt.expandSecretSubdirectories(args)
+			if err != nil {
+				return nil, err
+			}
+			all = append(all, expanded...)
+		}
+		for _, args := range all {
+			if kt.skipOptionalGenerator(args.GeneratorArgs) {
+				continue
+			}
 			c.SecretArgs = args
-			c.SecretArgs.Options = types.MergeGlobalOptionsIntoLocal(
-				c.SecretArgs.Options, kt.kustomization.GeneratorOptions)
+			c.SecretArgs.Options = kt.mergeGeneratorOptions(c.SecretArgs.Options)
 			p := f()
 			err := kt.configureBuiltinPlugin(p, c, bpt)
 			if err != nil {
@@ -99,9 +117,11 @@ var generatorConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			types.ConfigMapArgs
 		}
 		for _, args := range kt.kustomization.ConfigMapGenerator {
+			if kt.skipOptionalGenerator(args.GeneratorArgs) {
+				continue
+			}
 			c.ConfigMapArgs = args
-			c.ConfigMapArgs.Options = types.MergeGlobalOptionsIntoLocal(
-				c.ConfigMapArgs.Options, kt.kustomization.GeneratorOptions)
+			c.ConfigMapArgs.Options = kt.mergeGeneratorOptions(c.ConfigMapArgs.Options)
 			p := f()
 			err := kt.configureBuiltinPlugin(p, c, bpt)
 			if err != nil {
@@ -114,6 +134,10 @@ var generatorConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 
 	builtinhelpers.HelmChartInflationGenerator: func(kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f gFactory) (
 		result []resmap.Generator, err error) {
+		if kt.helmInflater != nil {
+			// The charts are rendered by accumulateHelmCharts.
+			return
+		}
 		var c struct {
 			types.HelmChartArgs
 		}
@@ -128,6 +152,194 @@ var generatorConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		}
 		return
 	},
+
+	builtinhelpers.JsonArrayGenerator: func(kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f gFactory) (
+		result []resmap.Generator, err error) {
+		var c struct {
+			types.JsonArrayArgs
+		}
+		for _, args := range kt.kustomization.JsonArrayGenerator {
+			c.JsonArrayArgs = args
+			p := f()
+			err := kt.configureBuiltinPlugin(p, c, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
+
+	builtinhelpers.ExternalSecretGenerator: func(kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f gFactory) (
+		result []resmap.Generator, err error) {
+		var c struct {
+			types.ExternalSecretArgs
+		}
+		for _, args := range kt.kustomization.ExternalSecretGenerator {
+			c.ExternalSecretArgs = args
+			p := f()
+			err := kt.configureBuiltinPlugin(p, c, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
+
+	builtinhelpers.GoTemplateGenerator: func(kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f gFactory) (
+		result []resmap.Generator, err error) {
+		var c struct {
+			types.GoTemplateArgs
+		}
+		for _, args := range kt.kustomization.GoTemplateGenerator {
+			c.GoTemplateArgs = args
+			p := f()
+			err := kt.configureBuiltinPlugin(p, c, bpt)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return
+	},
+}
+
+// skipOptionalGenerator returns true, after logging why, if the
+// generator is optional and a file it reads from can't be loaded.
+func (kt *KustTarget) skipOptionalGenerator(args types.GeneratorArgs) bool {
+	if !args.Optional {
+		return false
+	}
+	for _, p := range kv.SourcePaths(args.KvPairSources) {
+		if _, err := kt.ldr.Load(p); err != nil {
+			log.Printf(
+				"skipping optional generator '%s', as its source '%s' can't be loaded: %v",
+				args.Name, p, err)
+			return true
+		}
+	}
+	return false
+}
+
+// mergeGeneratorOptions merges the kustomization's global
+// generatorOptions into the given local options, then applies
+// any overrides demanded by the build options, and adds the
+// commonLabels if the options inherit them.
+func (kt *KustTarget) mergeGeneratorOptions(
+	local *types.GeneratorOptions) *types.GeneratorOptions {
+	opts := types.MergeGlobalOptionsIntoLocal(
+		local, kt.kustomization.GeneratorOptions)
+	if kt.buildOptions.DisableNameSuffixHash {
+		var o types.GeneratorOptions
+		if opts != nil {
+			o = *opts
+		}
+		o.DisableNameSuffixHash = true
+		opts = &o
+	}
+	if opts != nil && opts.InheritCommonLabels &&
+		len(kt.kustomization.CommonLabels) > 0 {
+		o := *opts
+		o.Labels = types.CopyMap(kt.kustomization.CommonLabels)
+		for k, v := range opts.Labels {
+			o.Labels[k] = v
+		}
+		opts = &o
+	}
+	return opts
+}
+
+// imagesForProfile returns the kustomization's image entries
+// that apply under the build profile, i.e. the entries that
+// belong to no profile plus those belonging to the build profile.
+func (kt *KustTarget) imagesForProfile() ([]types.Image, error) {
+	var result []types.Image
+	for _, img := range kt.kustomization.Images {
+		if img.Profile != "" && img.Profile != kt.buildOptions.Profile {
+			if err := kt.checkProfile("image"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		img, err := kt.imageTagFromEnv(img)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, img)
+	}
+	return result, nil
+}
+
+// checkProfile returns an error naming the given kind of entry
+// if the kustomization has entries of any kind assigned to
+// profiles, but none to the build profile.
+func (kt *KustTarget) checkProfile(kind string) error {
+	profiles := make(map[string]bool)
+	for _, img := range kt.kustomization.Images {
+		if img.Profile != "" {
+			profiles[img.Profile] = true
+		}
+	}
+	for p := range kt.kustomization.ProfileNamespaces {
+		profiles[p] = true
+	}
+	if kt.buildOptions.Profile == "" || len(profiles) == 0 ||
+		profiles[kt.buildOptions.Profile] {
+		return nil
+	}
+	var known []string
+	for p := range profiles {
+		known = append(known, p)
+	}
+	sort.Strings(known)
+	return fmt.Errorf(
+		"unknown %s profile '%s' in kustomization at '%s'; known profiles: %v",
+		kind, kt.buildOptions.Profile, kt.ldr.Root(), known)
+}
+
+// namespaceForProfile returns the namespace the kustomization
+// gives its objects under the build profile, i.e. the namespace
+// of the build profile if it has one, else the plain namespace.
+func (kt *KustTarget) namespaceForProfile() (string, error) {
+	if len(kt.kustomization.ProfileNamespaces) == 0 ||
+		kt.buildOptions.Profile == "" {
+		return kt.kustomization.Namespace, nil
+	}
+	if err := kt.checkProfile("namespace"); err != nil {
+		return "", err
+	}
+	if ns, ok := kt.kustomization.ProfileNamespaces[kt.buildOptions.Profile]; ok {
+		return ns, nil
+	}
+	return kt.kustomization.Namespace, nil
+}
+
+// imageTagFromEnv returns the image entry with its newTag
+// read from the environment variable named by its newTagEnv.
+func (kt *KustTarget) imageTagFromEnv(img types.Image) (types.Image, error) {
+	if img.NewTagEnv == "" {
+		return img, nil
+	}
+	if !kt.buildOptions.ImageTagsFromEnv {
+		return img, fmt.Errorf(
+			"image '%s' in kustomization at '%s' has newTagEnv, but image tags from the environment aren't enabled",
+			img.Name, kt.ldr.Root())
+	}
+	if img.NewTag != "" || img.Digest != "" || img.NewDigest != "" {
+		return img, fmt.Errorf(
+			"image '%s' in kustomization at '%s' has newTagEnv and another of newTag, digest or newDigest",
+			img.Name, kt.ldr.Root())
+	}
+	tag, ok := os.LookupEnv(img.NewTagEnv)
+	if !ok || tag == "" {
+		return img, fmt.Errorf(
+			"environment variable '%s' for the tag of image '%s' is not set",
+			img.NewTagEnv, img.Name)
+	}
+	img.NewTag = tag
+	img.NewTagEnv = ""
+	return img, nil
 }
 
 type tFactory func() resmap.TransformerPlugin
@@ -144,7 +356,13 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			types.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
 			FieldSpecs       []types.FieldSpec
 		}
-		c.Namespace = kt.kustomization.Namespace
+		c.Namespace, err = kt.namespaceForProfile()
+		if err != nil {
+			return nil, err
+		}
+		if c.Namespace == "" {
+			return
+		}
 		c.FieldSpecs = tc.NameSpace
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
@@ -162,7 +380,12 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
 			Path   string          `json:"path,omitempty" yaml:"path,omitempty"`
 			JsonOp string          `json:"jsonOp,omitempty" yaml:"jsonOp,omitempty"`
+
+			WarnOnNoOp    bool `json:"warnOnNoOp,omitempty" yaml:"warnOnNoOp,omitempty"`
+			ReportChanges bool `json:"reportChanges,omitempty" yaml:"reportChanges,omitempty"`
 		}
+		c.WarnOnNoOp = kt.buildOptions.WarnOnNoOpPatches
+		c.ReportChanges = kt.patchReports != nil
 		for _, args := range kt.kustomization.PatchesJson6902 {
 			c.Target = args.Target
 			c.Path = args.Path
@@ -184,8 +407,13 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		}
 		var c struct {
 			Paths []types.PatchStrategicMerge `json:"paths,omitempty" yaml:"paths,omitempty"`
+
+			WarnOnNoOp    bool `json:"warnOnNoOp,omitempty" yaml:"warnOnNoOp,omitempty"`
+			ReportChanges bool `json:"reportChanges,omitempty" yaml:"reportChanges,omitempty"`
 		}
 		c.Paths = kt.kustomization.PatchesStrategicMerge
+		c.WarnOnNoOp = kt.buildOptions.WarnOnNoOpPatches
+		c.ReportChanges = kt.patchReports != nil
 		p := f()
 		err = kt.configureBuiltinPlugin(p, c, bpt)
 		if err != nil {
@@ -201,10 +429,14 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			return
 		}
 		var c struct {
-			Path   string          `json:"path,omitempty" yaml:"path,omitempty"`
-			Patch  string          `json:"patch,omitempty" yaml:"patch,omitempty"`
-			Target *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+			Path          string          `json:"path,omitempty" yaml:"path,omitempty"`
+			Patch         string          `json:"patch,omitempty" yaml:"patch,omitempty"`
+			Target        *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+			WarnOnNoOp    bool            `json:"warnOnNoOp,omitempty" yaml:"warnOnNoOp,omitempty"`
+			ReportChanges bool            `json:"reportChanges,omitempty" yaml:"reportChanges,omitempty"`
 		}
+		c.WarnOnNoOp = kt.buildOptions.WarnOnNoOpPatches
+		c.ReportChanges = kt.patchReports != nil
 		for _, pc := range kt.kustomization.Patches {
 			c.Target = pc.Target
 			c.Patch = pc.Patch
@@ -221,6 +453,9 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 	builtinhelpers.LabelTransformer: func(
 		kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f tFactory, tc *builtinconfig.TransformerConfig) (
 		result []resmap.Transformer, err error) {
+		if len(kt.kustomization.CommonLabels) == 0 {
+			return
+		}
 		var c struct {
 			Labels     map[string]string
 			FieldSpecs []types.FieldSpec
@@ -235,9 +470,32 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		result = append(result, p)
 		return
 	},
+	builtinhelpers.RecommendedLabelsTransformer: func(
+		kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f tFactory, tc *builtinconfig.TransformerConfig) (
+		result []resmap.Transformer, err error) {
+		if kt.kustomization.RecommendedLabels == nil {
+			return
+		}
+		var c struct {
+			types.RecommendedLabels `json:",inline,omitempty" yaml:",inline,omitempty"`
+			FieldSpecs              []types.FieldSpec
+		}
+		c.RecommendedLabels = *kt.kustomization.RecommendedLabels
+		c.FieldSpecs = tc.CommonLabels
+		p := f()
+		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+		return
+	},
 	builtinhelpers.AnnotationsTransformer: func(
 		kt *KustTarget, bpt builtinhelpers.BuiltinPluginType, f tFactory, tc *builtinconfig.TransformerConfig) (
 		result []resmap.Transformer, err error) {
+		if len(kt.kustomization.CommonAnnotations) == 0 {
+			return
+		}
 		var c struct {
 			Annotations map[string]string
 			FieldSpecs  []types.FieldSpec
@@ -264,7 +522,13 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 		c.Suffix = kt.kustomization.NameSuffix
 		c.FieldSpecs = tc.NamePrefix
 		p := f()
-		err = kt.configureBuiltinPlugin(p, c, bpt)
+		if c.Prefix == "" && c.Suffix == "" {
+			// Without either, the transformer only notes the
+			// (empty) prefix and suffix of the resources.
+			err = kt.configurePlugin(p, c, bpt)
+		} else {
+			err = kt.configureBuiltinPlugin(p, c, bpt)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -278,7 +542,11 @@ var transformerConfigurators = map[builtinhelpers.BuiltinPluginType]func(
 			ImageTag   types.Image
 			FieldSpecs []types.FieldSpec
 		}
-		for _, args := range kt.kustomization.Images {
+		images, err := kt.imagesForProfile()
+		if err != nil {
+			return nil, err
+		}
+		for _, args := range images {
 			c.ImageTag = args
 			c.FieldSpecs = tc.Images
 			p := f()
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/target"
 	"sigs.k8s.io/kustomize/api/provider"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/resource"
@@ -257,3 +258,205 @@ metadata:
 	assert.NoError(t, err)
 	assert.Equal(t, expYaml, actYaml)
 }
+
+func TestMakeAccumulatedResMap(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/base", `
+namePrefix: base-
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: base-config
+  literals:
+  - a=b
+`)
+	th.WriteF("/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dply1
+`)
+	th.WriteK("/overlay", `
+namespace: ns1
+commonLabels:
+  app: nginx
+resources:
+- ../base
+- service.yaml
+secretGenerator:
+- name: secret
+  literals:
+  - c=d
+`)
+	th.WriteF("/overlay/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+`)
+
+	kt := makeAndLoadKustTarget(t, th.GetFSys(), "/overlay")
+	actual, err := kt.MakeAccumulatedResMap()
+	require.NoError(t, err)
+	actual.RemoveBuildAnnotations()
+	actYaml, err := actual.AsYaml()
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dply1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc1
+`, string(actYaml))
+
+	// The target can still be built in full afterwards.
+	full, err := kt.MakeCustomizedResMap()
+	require.NoError(t, err)
+	assert.Equal(t, 4, full.Size())
+}
+
+func TestMakeCustomizedResMapFrom(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+namePrefix: p-
+commonLabels:
+  app: web
+resources:
+- service.yaml
+configMapGenerator:
+- name: settings
+  behavior: merge
+  literals:
+  - mode=slow
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	rf := provider.NewDefaultDepProvider().GetResourceFactory()
+	base, err := resmap.NewFactory(rf, nil).NewResMapFromBytes([]byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: fast
+  size: large
+`))
+	require.NoError(t, err)
+
+	kt := makeAndLoadKustTarget(t, th.GetFSys(), "/app")
+	actual, err := kt.MakeCustomizedResMapFrom(base)
+	require.NoError(t, err)
+	actual.RemoveBuildAnnotations()
+	actYaml, err := actual.AsYaml()
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app: web
+  name: p-web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+---
+apiVersion: v1
+data:
+  mode: slow
+  size: large
+kind: ConfigMap
+metadata:
+  labels:
+    app: web
+  name: p-settings
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: web
+  name: p-web
+spec:
+  selector:
+    app: web
+`, string(actYaml))
+
+	// The resources given aren't changed.
+	assert.Equal(t, "web", base.Resources()[0].GetName())
+}
+
+func TestMakeCustomizedResMapFromConflict(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- service.yaml
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	rf := provider.NewDefaultDepProvider().GetResourceFactory()
+	base, err := resmap.NewFactory(rf, nil).NewResMapFromBytes([]byte(`
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`))
+	require.NoError(t, err)
+
+	kt := makeAndLoadKustTarget(t, th.GetFSys(), "/app")
+	_, err = kt.MakeCustomizedResMapFrom(base)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already registered id")
+}
+
+func TestBuildMetadataOfLatestBuild(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+- replicas.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/replicas.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	kt := makeAndLoadKustTarget(t, th.GetFSys(), "/app")
+	kt.SetBuildOptions(target.BuildOptions{RecordPatchChanges: true})
+	for i := 0; i < 2; i++ {
+		_, err := kt.MakeCustomizedResMap()
+		require.NoError(t, err)
+		assert.Len(t, kt.BuildMetadata().Patches, 1)
+	}
+}
@@ -6,13 +6,25 @@ package target
 import (
 	"fmt"
 
+	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
 )
 
 // multiTransformer contains a list of transformers.
 type multiTransformer struct {
 	transformers         []resmap.Transformer
 	checkConflictEnabled bool
+	// If not nil, notes which transformer changed what.
+	recorder *transformationRecorder
+	// If not nil, observe each transformer, of the
+	// kustomization at root, as it runs; the transformers
+	// then run one after the other, to be timed apart.
+	hooks ifc.BuildHooks
+	root  string
+	// The path of the component the transformers belong to,
+	// if they belong to one.
+	component string
 }
 
 var _ resmap.Transformer = &multiTransformer{}
@@ -36,12 +48,41 @@ func (o *multiTransformer) Transform(m resmap.ResMap) error {
 }
 
 func (o *multiTransformer) transform(m resmap.ResMap) error {
+	if o.recorder == nil && o.hooks == nil && m.Size() >= minParallelSize {
+		for _, g := range independentGroups(o.transformers) {
+			if err := transformConcurrently(m, g); err != nil {
+				return err
+			}
+		}
+		return o.removeEmpty(m)
+	}
 	for _, t := range o.transformers {
+		var before map[*resource.Resource]string
+		if o.recorder != nil {
+			before = o.recorder.snapshot(m)
+		}
+		end := o.startTransformer(t)
 		err := t.Transform(m)
 		if err != nil {
 			return err
 		}
+		end(m.Size())
+		if o.recorder != nil {
+			o.recorder.record(t, o.component, before, m)
+		}
 	}
+	return o.removeEmpty(m)
+}
+
+func (o *multiTransformer) startTransformer(t resmap.Transformer) func(int) {
+	if o.hooks == nil {
+		return func(int) {}
+	}
+	return startPhase(o.hooks, ifc.PhaseEvent{
+		Phase: ifc.PhaseTransform, Root: o.root, Name: transformerName(t)})
+}
+
+func (o *multiTransformer) removeEmpty(m resmap.ResMap) error {
 	for _, r := range m.Resources() {
 		empty, err := r.IsEmpty()
 		if err != nil {
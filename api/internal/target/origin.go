@@ -0,0 +1,121 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// setOrigins notes, in the resources made by the base at the
+// given path, that they came from that base.  Resources that
+// came from bases of that base keep the path to their base,
+// now relative to the kustomization that lists the path.
+func setOrigins(m resmap.ResMap, path string) {
+	base := cleanOrigin(path)
+	for _, r := range m.Resources() {
+		if origin := r.GetOrigin(); origin != "" {
+			r.SetOrigin(base + "/" + origin)
+		} else {
+			r.SetOrigin(base)
+		}
+	}
+}
+
+// addBaseProvenance notes, in the provenance of the resources
+// made by the base at the given path, that they came from it.
+func addBaseProvenance(m resmap.ResMap, path string) {
+	for _, r := range m.Resources() {
+		r.AddProvenance(types.ProvenanceStep{Base: cleanOrigin(path)})
+	}
+}
+
+// annotateProvenance gives the resources with a provenance an
+// annotation holding it.
+func annotateProvenance(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		steps := r.GetProvenance()
+		if len(steps) == 0 {
+			continue
+		}
+		value, err := json.Marshal(steps)
+		if err != nil {
+			return err
+		}
+		annotations := r.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[konfig.ProvenanceAnnotation] = string(value)
+		r.SetAnnotations(annotations)
+	}
+	return nil
+}
+
+// cleanOrigin cleans the path of a local base; the URL of a
+// remote base is kept as is.
+func cleanOrigin(path string) string {
+	if strings.Contains(path, "://") {
+		return strings.TrimSuffix(path, "/")
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// originScopedTransformer applies a transformer only to the
+// resources that came from the base at the given path.
+type originScopedTransformer struct {
+	transformer resmap.Transformer
+	base        string
+}
+
+var _ resmap.Transformer = &originScopedTransformer{}
+
+func (o *originScopedTransformer) Transform(m resmap.ResMap) error {
+	scoped := resmap.New()
+	for _, r := range m.Resources() {
+		if o.matches(r.GetOrigin()) {
+			if err := scoped.Append(r); err != nil {
+				return err
+			}
+		}
+	}
+	before := make(map[*resource.Resource]bool)
+	for _, r := range scoped.Resources() {
+		before[r] = true
+	}
+	if err := o.transformer.Transform(scoped); err != nil {
+		return err
+	}
+	// The transformer may have removed or replaced resources,
+	// or made new ones.
+	after := make(map[*resource.Resource]bool)
+	for _, r := range scoped.Resources() {
+		after[r] = true
+	}
+	for r := range before {
+		if !after[r] {
+			if err := m.Remove(r.CurId()); err != nil {
+				return err
+			}
+		}
+	}
+	for _, r := range scoped.Resources() {
+		if !before[r] {
+			if err := m.Append(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (o *originScopedTransformer) matches(origin string) bool {
+	return origin == o.base || strings.HasPrefix(origin, o.base+"/")
+}
@@ -0,0 +1,326 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/internal/utils"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// minParallelSize is the number of resources below which
+// copying the resmap for each transformer costs more than
+// running the transformers one after the other.
+const minParallelSize = 100
+
+// errMergeConflict means two transformers changed the same
+// field after all, so they must run sequentially.
+var errMergeConflict = errors.New("transformers changed the same field")
+
+// fieldFootprint returns the fields that the transformer may
+// change.  The second value is false unless the transformer is
+// known to change nothing but those fields, i.e. it neither
+// touches resource ids nor adds or removes resources.
+func fieldFootprint(t resmap.Transformer) (types.FsSlice, bool) {
+	switch p := t.(type) {
+	case *builtins.AnnotationsTransformerPlugin:
+		return p.FieldSpecs, true
+	case *builtins.LabelTransformerPlugin:
+		return p.FieldSpecs, true
+	case *builtins.RecommendedLabelsTransformerPlugin:
+		return p.FieldSpecs, true
+	case *builtins.ImageTagTransformerPlugin:
+		return p.FieldSpecs, true
+	case *builtins.ReplicaCountTransformerPlugin:
+		return p.FieldSpecs, true
+	default:
+		return nil, false
+	}
+}
+
+// overlaps returns true if a field of one slice is, or is
+// inside of, a field of the other.  Kinds are ignored, so
+// this errs on the side of overlap.
+func overlaps(a, b types.FsSlice) bool {
+	for _, x := range a {
+		for _, y := range b {
+			xp := utils.PathSplitter(x.Path)
+			yp := utils.PathSplitter(y.Path)
+			if isPathPrefix(xp, yp) || isPathPrefix(yp, xp) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isPathPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if strings.TrimSuffix(prefix[i], "[]") !=
+			strings.TrimSuffix(path[i], "[]") {
+			return false
+		}
+	}
+	return true
+}
+
+// independentGroups splits the transformers, keeping their
+// order, into runs whose members change disjoint fields and
+// so may be applied in any order, or at the same time.
+// Transformers with an unknown footprint are alone in a group.
+func independentGroups(transformers []resmap.Transformer) [][]resmap.Transformer {
+	var groups [][]resmap.Transformer
+	var group []resmap.Transformer
+	var fields []types.FsSlice
+	flush := func() {
+		if len(group) > 0 {
+			groups = append(groups, group)
+		}
+		group, fields = nil, nil
+	}
+	for _, t := range transformers {
+		fs, ok := fieldFootprint(t)
+		if !ok {
+			flush()
+			groups = append(groups, []resmap.Transformer{t})
+			continue
+		}
+		for _, other := range fields {
+			if overlaps(fs, other) {
+				flush()
+				break
+			}
+		}
+		group = append(group, t)
+		fields = append(fields, fs)
+	}
+	flush()
+	return groups
+}
+
+// transformConcurrently applies the independent transformers,
+// each to its own copy of the resources, at the same time, and
+// then merges their changes into m in transformer order.  The
+// result is the one sequential application would give.  If
+// the changes collide after all, the transformers are instead
+// applied one after the other.
+func transformConcurrently(
+	m resmap.ResMap, transformers []resmap.Transformer) error {
+	if len(transformers) < 2 {
+		return transformSequentially(m, transformers)
+	}
+	copies := make([]resmap.ResMap, len(transformers))
+	errs := make([]error, len(transformers))
+	var wg sync.WaitGroup
+	for i, t := range transformers {
+		copies[i] = m.DeepCopy()
+		wg.Add(1)
+		go func(i int, t resmap.Transformer) {
+			defer wg.Done()
+			errs[i] = t.Transform(copies[i])
+		}(i, t)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	err := mergeChanges(m, copies)
+	if err == errMergeConflict {
+		return transformSequentially(m, transformers)
+	}
+	return err
+}
+
+func transformSequentially(
+	m resmap.ResMap, transformers []resmap.Transformer) error {
+	for _, t := range transformers {
+		if err := t.Transform(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeChanges puts the changes that each copy holds relative
+// to m into the first copy and, if they don't collide, then
+// makes m hold the result.
+func mergeChanges(m resmap.ResMap, copies []resmap.ResMap) error {
+	base := m.Resources()
+	result := copies[0].Resources()
+	for _, c := range copies {
+		if c.Size() != len(base) {
+			return errMergeConflict
+		}
+	}
+	for i, r := range result {
+		baseNode, err := nodeOf(base[i])
+		if err != nil {
+			return err
+		}
+		var changed []*yaml.Node
+		for _, c := range copies[1:] {
+			n, err := nodeOf(c.Resources()[i])
+			if err != nil {
+				return err
+			}
+			if (n == nil) != (baseNode == nil) {
+				return errMergeConflict
+			}
+			changed = append(changed, n)
+		}
+		if baseNode == nil {
+			// an empty resource
+			continue
+		}
+		var mergeErr error
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				for _, n := range changed {
+					if mergeErr = mergeNode(node.YNode(), baseNode, n); mergeErr != nil {
+						break
+					}
+				}
+				return node, nil
+			})))
+		if err != nil {
+			return err
+		}
+		if mergeErr != nil {
+			return mergeErr
+		}
+	}
+	for i, r := range base {
+		r.ResetPrimaryData(result[i])
+	}
+	return nil
+}
+
+// nodeOf returns the yaml node holding the resource's data.
+func nodeOf(r *resource.Resource) (*yaml.Node, error) {
+	var result *yaml.Node
+	err := r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+		func(node *yaml.RNode) (*yaml.RNode, error) {
+			result = node.YNode()
+			return node, nil
+		})))
+	return result, err
+}
+
+// mergeNode makes to dst whatever change leads from base to
+// changed.  Parts of dst that differ from base must be left
+// alone by that change, else it's a conflict.  A missing base
+// counts as an empty map if dst and changed are both maps,
+// as happens when two transformers create the same parent.
+func mergeNode(dst, base, changed *yaml.Node) error {
+	if base != nil && nodesEqual(base, changed) {
+		return nil
+	}
+	if dst.Kind == yaml.MappingNode && changed.Kind == yaml.MappingNode &&
+		(base == nil || base.Kind == yaml.MappingNode) {
+		return mergeMapping(dst, base, changed)
+	}
+	if dst.Kind == yaml.SequenceNode && changed.Kind == yaml.SequenceNode &&
+		base != nil && base.Kind == yaml.SequenceNode &&
+		len(dst.Content) == len(base.Content) &&
+		len(changed.Content) == len(base.Content) {
+		for i := range changed.Content {
+			err := mergeNode(dst.Content[i], base.Content[i], changed.Content[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if base == nil || !nodesEqual(dst, base) {
+		return errMergeConflict
+	}
+	*dst = *yaml.CopyYNode(changed)
+	return nil
+}
+
+func mergeMapping(dst, base, changed *yaml.Node) error {
+	for i := 0; i < len(changed.Content); i += 2 {
+		key := changed.Content[i].Value
+		value := changed.Content[i+1]
+		var baseValue *yaml.Node
+		if base != nil {
+			baseValue = mapValue(base, key)
+		}
+		dstValue := mapValue(dst, key)
+		if dstValue == nil {
+			if baseValue != nil {
+				// removed by an earlier change
+				return errMergeConflict
+			}
+			dst.Content = append(dst.Content,
+				yaml.CopyYNode(changed.Content[i]), yaml.CopyYNode(value))
+			continue
+		}
+		if err := mergeNode(dstValue, baseValue, value); err != nil {
+			return err
+		}
+	}
+	if base == nil {
+		return nil
+	}
+	for i := 0; i < len(base.Content); i += 2 {
+		key := base.Content[i].Value
+		if mapValue(changed, key) != nil {
+			continue
+		}
+		dstValue := mapValue(dst, key)
+		if dstValue == nil {
+			continue
+		}
+		if !nodesEqual(dstValue, base.Content[i+1]) {
+			return errMergeConflict
+		}
+		removeMapKey(dst, key)
+	}
+	return nil
+}
+
+func mapValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func removeMapKey(n *yaml.Node, key string) {
+	for i := 0; i < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content = append(n.Content[:i], n.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+func nodesEqual(a, b *yaml.Node) bool {
+	if a.Kind != b.Kind || a.Tag != b.Tag || a.Value != b.Value ||
+		a.Style != b.Style || len(a.Content) != len(b.Content) {
+		return false
+	}
+	for i := range a.Content {
+		if !nodesEqual(a.Content[i], b.Content[i]) {
+			return false
+		}
+	}
+	return true
+}
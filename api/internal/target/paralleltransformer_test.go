@@ -0,0 +1,159 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/builtins"
+	"sigs.k8s.io/kustomize/api/internal/plugins/builtinconfig"
+	"sigs.k8s.io/kustomize/api/provider"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func makeLargeResMap(t testing.TB, n int) resmap.ResMap {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy%d
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: app
+        image: nginx:1.7.9
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc%d
+  annotations:
+    team: web
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: cron%d
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          initContainers:
+          - name: init
+            image: busybox
+`, i, i, i)
+	}
+	pvd := provider.NewDefaultDepProvider()
+	rf := resmap.NewFactory(
+		pvd.GetResourceFactory(), pvd.GetConflictDetectorFactory())
+	m, err := rf.NewResMapFromBytes([]byte(b.String()))
+	require.NoError(t, err)
+	return m
+}
+
+func makeIndependentTransformers() []resmap.Transformer {
+	tc := builtinconfig.MakeDefaultConfig()
+	return []resmap.Transformer{
+		&builtins.LabelTransformerPlugin{
+			Labels:     map[string]string{"app": "web"},
+			FieldSpecs: tc.CommonLabels,
+		},
+		&builtins.AnnotationsTransformerPlugin{
+			Annotations: map[string]string{"owner": "me"},
+			FieldSpecs:  tc.CommonAnnotations,
+		},
+		&builtins.ReplicaCountTransformerPlugin{
+			Replica:    types.Replica{Name: "deploy3", Count: 5},
+			FieldSpecs: tc.Replicas,
+		},
+		&builtins.ImageTagTransformerPlugin{
+			ImageTag:   types.Image{Name: "nginx", NewTag: "1.21"},
+			FieldSpecs: tc.Images,
+		},
+		&builtins.ImageTagTransformerPlugin{
+			ImageTag:   types.Image{Name: "busybox", NewName: "alpine"},
+			FieldSpecs: tc.Images,
+		},
+	}
+}
+
+func TestIndependentGroups(t *testing.T) {
+	ts := makeIndependentTransformers()
+	prefixer := &builtins.PrefixSuffixTransformerPlugin{Prefix: "a-"}
+	groups := independentGroups(
+		append([]resmap.Transformer{prefixer}, ts...))
+	assert.Equal(t, [][]resmap.Transformer{
+		{prefixer},
+		{ts[0], ts[1], ts[2], ts[3]},
+		{ts[4]},
+	}, groups)
+}
+
+func TestConcurrentTransformMatchesSequential(t *testing.T) {
+	m := makeLargeResMap(t, minParallelSize)
+	expected := m.DeepCopy()
+	require.NoError(t, transformSequentially(
+		expected, makeIndependentTransformers()))
+
+	require.NoError(t, (&multiTransformer{
+		transformers: makeIndependentTransformers()}).Transform(m))
+	actual, err := m.AsYaml()
+	require.NoError(t, err)
+	want, err := expected.AsYaml()
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(actual))
+}
+
+func TestConcurrentTransformConflict(t *testing.T) {
+	m := makeLargeResMap(t, 1)
+	fs := []types.FieldSpec{
+		{Path: "metadata/labels", CreateIfNotPresent: true}}
+	// Not independent, so they fall back to running in order.
+	ts := []resmap.Transformer{
+		&builtins.LabelTransformerPlugin{
+			Labels: map[string]string{"app": "a"}, FieldSpecs: fs},
+		&builtins.LabelTransformerPlugin{
+			Labels: map[string]string{"app": "b"}, FieldSpecs: fs},
+	}
+	require.NoError(t, transformConcurrently(m, ts))
+	for _, r := range m.Resources() {
+		assert.Equal(t, map[string]string{"app": "b"}, r.GetLabels())
+	}
+}
+
+func BenchmarkTransform(b *testing.B) {
+	m := makeLargeResMap(b, 300)
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			err := transformSequentially(
+				m.DeepCopy(), makeIndependentTransformers())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			err := (&multiTransformer{
+				transformers: makeIndependentTransformers(),
+			}).Transform(m.DeepCopy())
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
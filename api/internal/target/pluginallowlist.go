@@ -0,0 +1,43 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// errIfPluginDisallowed returns an error if the build options
+// allow only some builtin plugins, and not the given one.
+func (kt *KustTarget) errIfPluginDisallowed(id string) error {
+	if kt.buildOptions.AllowedPlugins == nil {
+		return nil
+	}
+	for _, allowed := range kt.buildOptions.AllowedPlugins {
+		if allowed == id {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"builtin plugin %s isn't allowed in this build, from '%s'",
+		id, kt.ldr.Root())
+}
+
+// errIfConfigsDisallowed returns an error if a config of a
+// builtin plugin, e.g. in the generators field, names a plugin
+// that isn't allowed.
+func (kt *KustTarget) errIfConfigsDisallowed(m resmap.ResMap) error {
+	for _, res := range m.Resources() {
+		gvk := res.GetGvk()
+		if gvk.Group != "" || gvk.Version != konfig.BuiltinPluginApiVersion {
+			continue
+		}
+		if err := kt.errIfPluginDisallowed(gvk.Kind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+)
+
+// Fields of a pod spec holding lists of containers whose
+// names must be unique across all the lists.
+var containerListFields = []string{
+	"initContainers",
+	"containers",
+}
+
+// errIfDuplicateContainerNames returns an error if any pod spec
+// in the given resources has two containers with the same name,
+// e.g. because a merge patch appended a container rather than
+// merging with an existing one.  The API server would reject
+// such an object.
+func errIfDuplicateContainerNames(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		for _, p := range resource.PodSpecPaths(r.GetKind()) {
+			err := errIfDuplicateContainerNamesAt(r, strings.Join(p, "."))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func errIfDuplicateContainerNamesAt(r *resource.Resource, podSpecPath string) error {
+	seen := make(map[string]bool)
+	for _, f := range containerListFields {
+		containers, err := r.GetSlice(podSpecPath + "." + f)
+		if err != nil {
+			// No such list in this resource.
+			continue
+		}
+		for _, c := range containers {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, ok := cm["name"].(string)
+			if !ok {
+				continue
+			}
+			if seen[name] {
+				return fmt.Errorf(
+					"resource %s has more than one container named '%s' in %s",
+					r.CurId(), name, podSpecPath)
+			}
+			seen[name] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// The keys a secret of a given type must hold.
+var secretTypeKeys = map[string][]string{
+	"kubernetes.io/tls":              {"tls.crt", "tls.key"},
+	"kubernetes.io/ssh-auth":         {"ssh-privatekey"},
+	"kubernetes.io/dockerconfigjson": {".dockerconfigjson"},
+	"kubernetes.io/dockercfg":        {".dockercfg"},
+}
+
+// expandSecretSubdirectories returns the args of the secrets
+// to generate from the given args, i.e. one per directory in
+// their subdirectories, else the args themselves.
+func (kt *KustTarget) expandSecretSubdirectories(
+	args types.SecretArgs) ([]types.SecretArgs, error) {
+	if args.Subdirectories == "" {
+		return []types.SecretArgs{args}, nil
+	}
+	if args.Name != "" {
+		return nil, fmt.Errorf(
+			"secret generator with subdirectories '%s' must not have a name '%s'",
+			args.Subdirectories, args.Name)
+	}
+	lister, ok := kt.ldr.(ifc.DirLister)
+	if !ok {
+		return nil, fmt.Errorf(
+			"cannot list subdirectories '%s' from '%s'",
+			args.Subdirectories, kt.ldr.Root())
+	}
+	dirs, _, err := lister.ListDir(args.Subdirectories)
+	if err != nil {
+		return nil, err
+	}
+	var result []types.SecretArgs
+	for _, dir := range dirs {
+		path := filepath.Join(args.Subdirectories, dir)
+		_, files, err := lister.ListDir(path)
+		if err != nil {
+			return nil, err
+		}
+		if err = errIfMissingSecretKeys(path, args.Type, files); err != nil {
+			return nil, err
+		}
+		a := args
+		a.Name = dir
+		a.Subdirectories = ""
+		a.FileSources = append([]string{}, args.FileSources...)
+		for _, f := range files {
+			a.FileSources = append(a.FileSources, filepath.Join(path, f))
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+func errIfMissingSecretKeys(dir, secretType string, files []string) error {
+	for _, key := range secretTypeKeys[secretType] {
+		found := false
+		for _, f := range files {
+			if f == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf(
+				"secret directory '%s' lacks the key '%s' of type '%s'",
+				dir, key, secretType)
+		}
+	}
+	return nil
+}
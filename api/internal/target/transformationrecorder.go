@@ -0,0 +1,104 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package target
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// transformationRecorder notes which transformers changed
+// which resources.  One recorder is shared by a target and
+// all the targets it recurses into, so that resources from
+// bases carry the record of what was done to them there.
+type transformationRecorder struct {
+	applied map[*resource.Resource][]string
+	// If true, the changes are noted in the provenance of
+	// the resources too.
+	provenance bool
+}
+
+func newTransformationRecorder(provenance bool) *transformationRecorder {
+	return &transformationRecorder{
+		applied:    make(map[*resource.Resource][]string),
+		provenance: provenance,
+	}
+}
+
+// snapshot returns a copy of each resource in the map,
+// keyed by the resource it was copied from.
+func (tr *transformationRecorder) snapshot(
+	m resmap.ResMap) map[*resource.Resource]string {
+	result := make(map[*resource.Resource]string, m.Size())
+	for _, r := range m.Resources() {
+		result[r] = comparableYaml(r)
+	}
+	return result
+}
+
+// record attributes to the given transformer, of the given
+// component if not empty, every resource that differs from its
+// snapshot, or has none because the transformer added it.
+func (tr *transformationRecorder) record(
+	t resmap.Transformer, component string,
+	before map[*resource.Resource]string, m resmap.ResMap) {
+	name := transformerName(t)
+	for _, r := range m.Resources() {
+		if y, ok := before[r]; ok && y == comparableYaml(r) {
+			continue
+		}
+		tr.applied[r] = append(tr.applied[r], name)
+		if tr.provenance {
+			r.AddProvenance(types.ProvenanceStep{
+				Component: component, Transformer: name})
+		}
+	}
+}
+
+// summarize returns, for every resource in the given map,
+// the transformers that changed it.
+func (tr *transformationRecorder) summarize(
+	m resmap.ResMap) []types.Transformation {
+	result := make([]types.Transformation, 0, m.Size())
+	for _, r := range m.Resources() {
+		names := tr.applied[r]
+		if names == nil {
+			names = []string{}
+		}
+		result = append(result, types.Transformation{
+			Resource: r.CurId(), Transformers: names})
+	}
+	return result
+}
+
+// comparableYaml returns the resource as YAML without the
+// annotations kustomize uses for its own bookkeeping, so that
+// only changes a user would see in the output count.
+func comparableYaml(r *resource.Resource) string {
+	c := r.DeepCopy()
+	c.RemoveBuildAnnotations()
+	y, err := c.AsYAML()
+	if err != nil {
+		return err.Error()
+	}
+	return string(y)
+}
+
+// transformerName returns a short, human readable name for
+// the transformer, e.g. "PatchTransformer" for a builtin one.
+func transformerName(t resmap.Transformer) string {
+	if o, ok := t.(*originScopedTransformer); ok {
+		t = o.transformer
+	}
+	n := fmt.Sprintf("%T", t)
+	n = strings.TrimPrefix(n, "*")
+	if i := strings.LastIndex(n, "."); i >= 0 {
+		n = n[i+1:]
+	}
+	return strings.TrimSuffix(n, "Plugin")
+}
@@ -61,6 +61,22 @@ const (
 	// If a resource has this annotation, kustomize will drop it.
 	IgnoredByKustomizeAnnotation = ConfigAnnoDomain + "/local-config"
 
+	// If the config of a transformer has this annotation, the
+	// transformer only changes the resources that came from the
+	// base at the given path, relative to the kustomization that
+	// lists the transformer, including those of the bases of that
+	// base.
+	OriginBaseAnnotation = ConfigAnnoDomain + "/origin-base"
+
+	// Generated resources have this annotation if their generator
+	// options name a field manager for server side apply.
+	FieldManagerAnnotation = "kustomize.config.k8s.io/field-manager"
+
+	// If asked to, the build gives every resource in its output
+	// that came from a base, or that a transformer changed, this
+	// annotation, holding the JSON list of those steps.
+	ProvenanceAnnotation = "kustomize.config.k8s.io/provenance"
+
 	// Label key that indicates the resources are built from Kustomize
 	ManagedbyLabelKey = "app.kubernetes.io/managed-by"
 
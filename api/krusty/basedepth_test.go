@@ -0,0 +1,66 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// writeBaseChain writes an app including base a, which
+// includes base b, which includes base c.
+func writeBaseChain(th kusttest_test.Harness) {
+	th.WriteK("/app", `
+resources:
+- ../a
+`)
+	th.WriteK("/a", `
+resources:
+- ../b
+`)
+	th.WriteK("/b", `
+resources:
+- ../c
+`)
+	th.WriteK("/c", `
+resources:
+- cm.yaml
+`)
+	th.WriteF("/c/cm.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+}
+
+func TestMaxBaseDepth(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeBaseChain(th)
+	opts := th.MakeDefaultOptions()
+	opts.MaxBaseDepth = 3
+	th.AssertActualEqualsExpected(th.Run("/app", opts), `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+}
+
+func TestMaxBaseDepthExceeded(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeBaseChain(th)
+	opts := th.MakeDefaultOptions()
+	opts.MaxBaseDepth = 2
+	err := th.RunWithErr("/app", opts)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"cannot include '../c' from '/b', as bases may only be included 2 deep") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
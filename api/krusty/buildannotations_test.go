@@ -0,0 +1,119 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeBuildAnnotationsKustomization(th kusttest_test.Harness) {
+	th.WriteK("base", `
+resources:
+- resources.yaml
+`)
+	th.WriteF("base/resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+  labels:
+    build.example.com/stage: canary
+spec:
+  containers:
+  - name: app
+    image: app
+    envFrom:
+    - configMapRef:
+        name: settings
+`)
+	th.WriteK("overlay", `
+namePrefix: p-
+resources:
+- ../base
+patches:
+- target:
+    labelSelector: build.example.com/stage=canary
+  patch: |-
+    - op: add
+      path: /spec/containers/0/args
+      value: [--canary]
+`)
+}
+
+func TestBuildAnnotationsRemoved(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeBuildAnnotationsKustomization(th)
+	opts := th.MakeDefaultOptions()
+	opts.BuildMetadataKeys = []string{"build.example.com/stage"}
+	m := th.Run("overlay", opts)
+	// The patch found its target by the label, and the
+	// reference followed the rename, yet neither the label
+	// nor the annotations recording the rename are emitted.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: p-settings
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: p-app
+spec:
+  containers:
+  - args:
+    - --canary
+    envFrom:
+    - configMapRef:
+        name: p-settings
+    image: app
+    name: app
+`)
+}
+
+func TestKeepBuildAnnotations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeBuildAnnotationsKustomization(th)
+	opts := th.MakeDefaultOptions()
+	opts.BuildMetadataKeys = []string{"build.example.com/stage"}
+	opts.KeepBuildAnnotations = true
+	m := th.Run("overlay", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    config.kubernetes.io/prefixes: p-
+    config.kubernetes.io/previousNames: settings
+    config.kubernetes.io/previousNamespaces: default
+  name: p-settings
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    config.kubernetes.io/prefixes: p-
+    config.kubernetes.io/previousNames: app,app
+    config.kubernetes.io/previousNamespaces: default,default
+  labels:
+    build.example.com/stage: canary
+  name: p-app
+spec:
+  containers:
+  - args:
+    - --canary
+    envFrom:
+    - configMapRef:
+        name: p-settings
+    image: app
+    name: app
+`)
+}
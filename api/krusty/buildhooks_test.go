@@ -0,0 +1,56 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/api/ifc"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+type recordingHooks struct {
+	started, ended []ifc.PhaseEvent
+}
+
+func (h *recordingHooks) PhaseStart(e ifc.PhaseEvent) {
+	h.started = append(h.started, e)
+}
+
+func (h *recordingHooks) PhaseEnd(e ifc.PhaseEvent) {
+	h.ended = append(h.ended, e)
+}
+
+func TestBuildHooks(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+namePrefix: shop-
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+`)
+	hooks := &recordingHooks{}
+	opts := th.MakeDefaultOptions()
+	opts.BuildHooks = hooks
+	th.Run("/app", opts)
+
+	var generate []ifc.PhaseEvent
+	var transformers []string
+	for _, e := range hooks.ended {
+		assert.Equal(t, "/app", e.Root)
+		switch e.Phase {
+		case ifc.PhaseGenerate:
+			generate = append(generate, e)
+		case ifc.PhaseTransform:
+			transformers = append(transformers, e.Name)
+		}
+	}
+	if assert.Len(t, generate, 1) {
+		assert.Equal(t, 1, generate[0].Count)
+	}
+	assert.Contains(t, transformers, "PrefixSuffixTransformer")
+	assert.Equal(t, len(hooks.started), len(hooks.ended))
+}
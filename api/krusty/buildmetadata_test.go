@@ -0,0 +1,285 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resid"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+func TestBuildMetadataTransformations(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+commonLabels:
+  app: web
+patches:
+- target:
+    kind: Deployment
+  patch: |-
+    - op: replace
+      path: /spec/replicas
+      value: 3
+`)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: web
+  labels:
+    app: web
+`)
+	opts := th.MakeDefaultOptions()
+	m, md, err := krusty.MakeKustomizer(&opts).
+		RunWithBuildMetadata(th.GetFSys(), "/app")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 2, m.Size())
+	assert.Equal(t, []types.Transformation{
+		{
+			Resource: resid.NewResId(resid.Gvk{
+				Group: "apps", Version: "v1", Kind: "Deployment"}, "web"),
+			Transformers: []string{"PatchTransformer", "LabelTransformer"},
+		},
+		{
+			Resource: resid.NewResId(resid.Gvk{
+				Version: "v1", Kind: "ServiceAccount"}, "web"),
+			Transformers: []string{},
+		},
+	}, md.Transformations)
+	y, err := json.Marshal(md)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, string(y), `"transformers":["PatchTransformer","LabelTransformer"]`)
+}
+
+func TestBuildMetadataNoTransformers(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- cm.yaml
+`)
+	th.WriteF("/app/cm.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+`)
+	opts := th.MakeDefaultOptions()
+	_, md, err := krusty.MakeKustomizer(&opts).
+		RunWithBuildMetadata(th.GetFSys(), "/app")
+	assert.NoError(t, err)
+	assert.Equal(t, []types.Transformation{{
+		Resource:     resid.NewResId(resid.Gvk{Version: "v1", Kind: "ConfigMap"}, "cm"),
+		Transformers: []string{},
+	}}, md.Transformations)
+}
+
+func TestBuildMetadataDependencies(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+`)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+      volumes:
+      - name: settings
+        configMap:
+          name: settings
+---
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: web-1
+  ownerReferences:
+  - apiVersion: apps/v1
+    kind: Deployment
+    name: web
+`)
+	opts := th.MakeDefaultOptions()
+	_, md, err := krusty.MakeKustomizer(&opts).
+		RunWithBuildMetadata(th.GetFSys(), "/app")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	deployment := resid.NewResId(resid.Gvk{
+		Group: "apps", Version: "v1", Kind: "Deployment"}, "web")
+	assert.Equal(t, []types.Dependency{
+		{
+			From:  deployment,
+			To:    resid.NewResId(resid.Gvk{Version: "v1", Kind: "ConfigMap"}, "settings-t82mkhg8fd"),
+			Field: "spec/template/spec/volumes/configMap/name",
+		},
+		{
+			From: resid.NewResId(resid.Gvk{
+				Group: "apps", Version: "v1", Kind: "ReplicaSet"}, "web-1"),
+			To:    deployment,
+			Field: "metadata/ownerReferences",
+		},
+	}, md.Dependencies)
+	_, err = json.Marshal(md.Dependencies)
+	assert.NoError(t, err)
+}
+
+func TestBuildMetadataPatches(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+- replicas.yaml
+- noop.yaml
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/replicas.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	th.WriteF("/app/noop.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	opts := th.MakeDefaultOptions()
+	_, md, err := krusty.MakeKustomizer(&opts).
+		RunWithBuildMetadata(th.GetFSys(), "/app")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	deployment := resid.NewResId(resid.Gvk{
+		Group: "apps", Version: "v1", Kind: "Deployment"}, "web")
+	assert.Equal(t, []types.PatchReport{
+		{Patch: "replicas.yaml", Target: deployment, Fields: []string{"spec.replicas"}},
+		{Patch: "noop.yaml", Target: deployment, Fields: []string{}},
+	}, md.Patches)
+}
+
+func TestBuildMetadataJsonPatches(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web
+  patch: |-
+    - op: replace
+      path: /spec/replicas
+      value: 3
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	opts := th.MakeDefaultOptions()
+	_, md, err := krusty.MakeKustomizer(&opts).
+		RunWithBuildMetadata(th.GetFSys(), "/app")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, []types.PatchReport{{
+		Patch: "inline patch", Target: resid.NewResId(resid.Gvk{
+			Group: "apps", Version: "v1", Kind: "Deployment"}, "web"),
+		Fields: []string{"spec.replicas"},
+	}}, md.Patches)
+}
+
+func TestBuildMetadataPatchesField(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patches:
+- path: replicas.yaml
+- target:
+    kind: Deployment
+  patch: |-
+    - op: add
+      path: /metadata/labels
+      value:
+        app: web
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/replicas.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	opts := th.MakeDefaultOptions()
+	_, md, err := krusty.MakeKustomizer(&opts).
+		RunWithBuildMetadata(th.GetFSys(), "/app")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	web := resid.NewResId(resid.Gvk{
+		Group: "apps", Version: "v1", Kind: "Deployment"}, "web")
+	assert.Equal(t, []types.PatchReport{{
+		Patch: "replicas.yaml", Target: web,
+		Fields: []string{"spec.replicas"},
+	}, {
+		Patch: "inline patch", Target: web,
+		Fields: []string{"metadata.labels"},
+	}}, md.Patches)
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestBuildOnlyAnnotation(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- resources.yaml
+vars:
+- name: HOST
+  objref:
+    apiVersion: v1
+    kind: ConfigMap
+    name: scaffold
+  fieldref:
+    fieldpath: data.host
+`)
+	th.WriteF("resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: scaffold
+  annotations:
+    kustomize.local/build-only: "true"
+data:
+  host: db.example.com
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: kept
+  annotations:
+    kustomize.local/build-only: "false"
+    note: kept
+spec:
+  containers:
+  - name: app
+    image: app
+    args:
+    - --db=$(HOST)
+`)
+	opts := th.MakeDefaultOptions()
+	opts.BuildOnlyAnnotation = "kustomize.local/build-only"
+	m := th.Run(".", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Pod
+metadata:
+  annotations:
+    note: kept
+  name: kept
+spec:
+  containers:
+  - args:
+    - --db=db.example.com
+    image: app
+    name: app
+`)
+}
+
+func TestBuildOnlyAnnotationWarnsOnReference(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- resources.yaml
+`)
+	th.WriteF("resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  annotations:
+    kustomize.local/build-only: "true"
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  volumes:
+  - name: config
+    configMap:
+      name: config
+`)
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	opts := th.MakeDefaultOptions()
+	opts.BuildOnlyAnnotation = "kustomize.local/build-only"
+	m := th.Run(".", opts)
+	assert.Equal(t, 1, m.Size())
+	assert.Contains(t, buf.String(),
+		"dropping build-only resource ~G_v1_ConfigMap|~X|config "+
+			"still referred to by ~G_v1_Pod|~X|pod")
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// writeStampingApp writes an app generating a ConfigMap, from
+// both a kustomization field and the generators field, whose
+// annotation stamps the time of the build.
+func writeStampingApp(th kusttest_test.Harness) {
+	th.WriteF("/app/stamp.yaml.tmpl", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .name }}
+  annotations:
+    built-at: "{{ now.UTC.Format "2006-01-02T15:04:05.000000000Z" }}"
+`)
+	th.WriteK("/app", `
+goTemplateGenerator:
+- template: stamp.yaml.tmpl
+  values:
+    name: from-field
+generators:
+- generator.yaml
+`)
+	th.WriteF("/app/generator.yaml", `
+apiVersion: builtin
+kind: GoTemplateGenerator
+metadata:
+  name: notImportantHere
+template: stamp.yaml.tmpl
+values:
+  name: from-generators
+`)
+}
+
+func TestBuildTime(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeStampingApp(th)
+	opts := th.MakeDefaultOptions()
+	opts.BuildTime = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    built-at: "2021-03-04T05:06:07.000000000Z"
+  name: from-field
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  annotations:
+    built-at: "2021-03-04T05:06:07.000000000Z"
+  name: from-generators
+`)
+	first, err := m.AsYaml()
+	assert.NoError(t, err)
+	second, err := th.Run("/app", opts).AsYaml()
+	assert.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+}
+
+func TestBuildTimeNotFixed(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeStampingApp(th)
+	before := time.Now().UTC()
+	m := th.Run("/app", th.MakeDefaultOptions())
+	for _, r := range m.Resources() {
+		stamp, err := time.Parse(time.RFC3339Nano, r.GetAnnotations()["built-at"])
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		assert.False(t, stamp.Before(before.Truncate(time.Second)),
+			"%s stamped %s, before the build", r.GetName(), stamp)
+	}
+}
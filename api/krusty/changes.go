@@ -0,0 +1,46 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"bytes"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// changesSince returns the resources of the current build that
+// the previous build didn't emit, or emitted with other content,
+// along with the ids of the resources only the previous build
+// emitted.
+func changesSince(previous, current resmap.ResMap) (
+	resmap.ResMap, []resid.ResId, error) {
+	changed := resmap.New()
+	for _, r := range current.Resources() {
+		old, err := previous.GetByCurrentId(r.CurId())
+		if err == nil {
+			oldYaml, err := old.AsYAML()
+			if err != nil {
+				return nil, nil, err
+			}
+			newYaml, err := r.AsYAML()
+			if err != nil {
+				return nil, nil, err
+			}
+			if bytes.Equal(oldYaml, newYaml) {
+				continue
+			}
+		}
+		if err := changed.Append(r); err != nil {
+			return nil, nil, err
+		}
+	}
+	var deleted []resid.ResId
+	for _, r := range previous.Resources() {
+		if _, err := current.GetByCurrentId(r.CurId()); err != nil {
+			deleted = append(deleted, r.CurId())
+		}
+	}
+	return changed, deleted, nil
+}
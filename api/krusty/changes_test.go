@@ -0,0 +1,98 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/resid"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeChangingApp(th kusttest_test.Harness, tag string, resources string) {
+	th.WriteK(".", `
+resources:
+`+resources+`
+images:
+- name: web
+  newTag: "`+tag+`"
+`)
+	th.WriteF("deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+`)
+	th.WriteF("service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+`)
+	th.WriteF("configmap.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`)
+}
+
+func TestPreviousBuild(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeChangingApp(th, "1.0", `
+- deployment.yaml
+- service.yaml
+- configmap.yaml
+`)
+	previous := th.Run(".", th.MakeDefaultOptions())
+
+	writeChangingApp(th, "1.1", `
+- deployment.yaml
+- service.yaml
+`)
+	opts := th.MakeDefaultOptions()
+	opts.PreviousBuild = previous
+	m, md, err := krusty.MakeKustomizer(&opts).RunWithBuildMetadata(
+		th.GetFSys(), ".")
+	require.NoError(t, err)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: web:1.1
+        name: web
+`)
+	assert.Equal(t, []resid.ResId{
+		resid.NewResId(resid.Gvk{Version: "v1", Kind: "ConfigMap"}, "settings"),
+	}, md.Deleted)
+}
+
+func TestPreviousBuildUnchanged(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeChangingApp(th, "1.0", `
+- deployment.yaml
+- service.yaml
+`)
+	opts := th.MakeDefaultOptions()
+	opts.PreviousBuild = th.Run(".", th.MakeDefaultOptions())
+	m := th.Run(".", opts)
+	assert.Equal(t, 0, m.Size())
+}
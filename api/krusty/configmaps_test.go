@@ -4,7 +4,11 @@
 package krusty_test
 
 import (
+	"bytes"
 	"fmt"
+	"log"
+	"os"
+	"strings"
 	"testing"
 
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
@@ -119,6 +123,151 @@ metadata:
 `)
 }
 
+func TestGeneratorFromValuesFile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("app", `
+configMapGenerator:
+- name: settings
+  valuesFiles:
+  - values.yaml
+  literals:
+  - mode=debug
+`)
+	th.WriteF("app/values.yaml", `
+host: db.example.com
+port: "5432"
+mode: release
+`)
+	m := th.Run("app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `apiVersion: v1
+data:
+  host: db.example.com
+  mode: debug
+  port: "5432"
+kind: ConfigMap
+metadata:
+  name: settings-4c5gk7t975
+`)
+}
+
+func TestGeneratorNameIgnoresKeyOrder(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/hostfirst", `
+configMapGenerator:
+- name: settings
+  literals:
+  - host=db
+  - port=8080
+`)
+	th.WriteK("/portfirst", `
+configMapGenerator:
+- name: settings
+  literals:
+  - port=8080
+  - host=db
+`)
+	th.WriteK("/fromenv", `
+configMapGenerator:
+- name: settings
+  envs:
+  - settings.env
+`)
+	th.WriteF("/fromenv/settings.env", "port=8080\nhost=db\n")
+	var names []string
+	for _, app := range []string{"/hostfirst", "/portfirst", "/fromenv"} {
+		m := th.Run(app, th.MakeDefaultOptions())
+		names = append(names, m.Resources()[0].GetName())
+	}
+	if names[0] != names[1] || names[0] != names[2] {
+		t.Fatalf("expected the same names, got %v", names)
+	}
+}
+
+func TestGeneratorFromValuesFileStrict(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("app", `
+configMapGenerator:
+- name: settings
+  valuesFiles:
+  - values.yaml
+  literals:
+  - mode=debug
+  strictValuesFiles: true
+`)
+	th.WriteF("app/values.yaml", `
+host: db.example.com
+mode: release
+`)
+	err := th.RunWithErr("app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"key 'mode' is in both values files [values.yaml] and literals") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGeneratorContentTransform(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("app", `
+configMapGenerator:
+- name: trimmed
+  files:
+  - token
+  transform:
+  - trimSpace
+- name: pretty
+  files:
+  - settings.json
+  transform:
+  - jsonPretty
+`)
+	th.WriteF("app/token", "abc123\n\n")
+	th.WriteF("app/settings.json", `{"debug":true,"hosts":["a","b"]}`)
+	m := th.Run("app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `apiVersion: v1
+data:
+  token: abc123
+kind: ConfigMap
+metadata:
+  name: trimmed-b6tm567bh7
+---
+apiVersion: v1
+data:
+  settings.json: |
+    {
+      "debug": true,
+      "hosts": [
+        "a",
+        "b"
+      ]
+    }
+kind: ConfigMap
+metadata:
+  name: pretty-tc2d955btm
+`)
+}
+
+func TestGeneratorUnknownContentTransform(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("app", `
+configMapGenerator:
+- name: cm
+  literals:
+  - a=b
+  transform:
+  - upperCase
+`)
+	err := th.RunWithErr("app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "unknown transform 'upperCase'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 // Generate a Secret and a ConfigMap from the same data
 // to compare the result.
 func TestGeneratorBasics(t *testing.T) {
@@ -561,3 +710,256 @@ metadata:
   name: testing-tt4769fb52
 `)
 }
+
+func TestGeneratorFinalizers(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+generatorOptions:
+  finalizers:
+  - example.com/cleanup
+`)
+	th.WriteK("/plain", `
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  finalizers:
+  - example.com/cleanup
+  name: settings-t82mkhg8fd
+`)
+	plain := th.Run("/plain", th.MakeDefaultOptions())
+	if name := plain.Resources()[0].GetName(); name != m.Resources()[0].GetName() {
+		t.Fatalf("finalizers changed the name suffix hash; without them got %s", name)
+	}
+}
+
+func TestGeneratorInheritCommonLabels(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("app", `
+commonLabels:
+  app: web
+  team: a
+resources:
+- service.yaml
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+  options:
+    inheritCommonLabels: true
+    labels:
+      team: b
+`)
+	th.WriteF("app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	m := th.Run("app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: web
+    team: a
+  name: web
+spec:
+  selector:
+    app: web
+    team: a
+---
+apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  labels:
+    app: web
+    team: b
+  name: settings-t82mkhg8fd
+`)
+}
+
+func TestGeneratorFieldManager(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+secretGenerator:
+- name: token
+  literals:
+  - token=abc
+  options:
+    fieldManager: deployer
+generatorOptions:
+  fieldManager: platform
+`)
+	th.WriteK("/plain", `
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  annotations:
+    kustomize.config.k8s.io/field-manager: platform
+  name: settings-t82mkhg8fd
+---
+apiVersion: v1
+data:
+  token: YWJj
+kind: Secret
+metadata:
+  annotations:
+    kustomize.config.k8s.io/field-manager: deployer
+  name: token-dbdgd77ct8
+type: Opaque
+`)
+	th.AssertActualEqualsExpected(th.Run("/plain", th.MakeDefaultOptions()), `
+apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  name: settings-t82mkhg8fd
+`)
+}
+
+func TestGeneratorOptionalMissingFile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+- name: local
+  optional: true
+  files:
+  - local.properties
+secretGenerator:
+- name: token
+  optional: true
+  envs:
+  - token.env
+`)
+	th.WriteF("/app/token.env", "token=abc\n")
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  name: settings-t82mkhg8fd
+---
+apiVersion: v1
+data:
+  token: YWJj
+kind: Secret
+metadata:
+  name: token-dbdgd77ct8
+type: Opaque
+`)
+	if !strings.Contains(buf.String(),
+		"skipping optional generator 'local', as its source 'local.properties'") {
+		t.Fatalf("expected a warning, got: %s", buf.String())
+	}
+}
+
+func TestGeneratorMissingFileNotOptional(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+configMapGenerator:
+- name: local
+  files:
+  - local.properties
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "local.properties") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSecretGeneratorSubdirectories(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+secretGenerator:
+- subdirectories: certs
+  type: kubernetes.io/tls
+  options:
+    disableNameSuffixHash: true
+`)
+	th.WriteF("/app/certs/api.example.com/tls.crt", "api-crt")
+	th.WriteF("/app/certs/api.example.com/tls.key", "api-key")
+	th.WriteF("/app/certs/www.example.com/tls.crt", "www-crt")
+	th.WriteF("/app/certs/www.example.com/tls.key", "www-key")
+	th.WriteF("/app/certs/README", "not a secret")
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  tls.crt: YXBpLWNydA==
+  tls.key: YXBpLWtleQ==
+kind: Secret
+metadata:
+  name: api.example.com
+type: kubernetes.io/tls
+---
+apiVersion: v1
+data:
+  tls.crt: d3d3LWNydA==
+  tls.key: d3d3LWtleQ==
+kind: Secret
+metadata:
+  name: www.example.com
+type: kubernetes.io/tls
+`)
+}
+
+func TestSecretGeneratorSubdirectoryMissingKey(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+secretGenerator:
+- subdirectories: certs
+  type: kubernetes.io/tls
+`)
+	th.WriteF("/app/certs/api.example.com/tls.crt", "api-crt")
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"secret directory 'certs/api.example.com' lacks the key 'tls.key'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+// writeEnvApp writes an app whose patches add env vars in the
+// given order.
+func writeEnvApp(th kusttest_test.Harness, dir string, patches string) {
+	th.WriteK(dir, `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+`+patches)
+	th.WriteF(dir+"/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+        env:
+        - name: ZONE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.labels['zone']
+        - name: Z_HOST
+          value: db
+        - name: A_URL
+          value: http://$(Z_HOST)/app
+`)
+	th.WriteF(dir+"/cache.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        env:
+        - name: CACHE
+          value: "on"
+`)
+	th.WriteF(dir+"/debug.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        env:
+        - name: DEBUG
+          value: "true"
+`)
+}
+
+func TestSortContainerEnv(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeEnvApp(th, "/cachefirst", "- cache.yaml\n- debug.yaml\n")
+	writeEnvApp(th, "/debugfirst", "- debug.yaml\n- cache.yaml\n")
+	opts := th.MakeDefaultOptions()
+	opts.SortContainerEnv = true
+	expected := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: CACHE
+          value: "on"
+        - name: DEBUG
+          value: "true"
+        - name: ZONE
+          valueFrom:
+            fieldRef:
+              fieldPath: metadata.labels['zone']
+        - name: Z_HOST
+          value: db
+        - name: A_URL
+          value: http://$(Z_HOST)/app
+        image: web
+        name: web
+`
+	th.AssertActualEqualsExpected(th.Run("/cachefirst", opts), expected)
+	th.AssertActualEqualsExpected(th.Run("/debugfirst", opts), expected)
+}
+
+func TestSortContainerEnvDuplicateNames(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- pod.yaml
+`)
+	th.WriteF("/app/pod.yaml", `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: web
+    image: web
+    env:
+    - name: MODE
+      value: slow
+    - name: LEVEL
+      value: info
+    - name: MODE
+      value: fast
+`)
+	opts := th.MakeDefaultOptions()
+	opts.SortContainerEnv = true
+	th.AssertActualEqualsExpected(th.Run("/app", opts), `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - env:
+    - name: MODE
+      value: slow
+    - name: LEVEL
+      value: info
+    - name: MODE
+      value: fast
+    image: web
+    name: web
+`)
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestDefaultResourceQuotaInBuildNamespace(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`)
+	th.WriteK(".", `
+namespace: team
+resources:
+- deployment.yaml
+transformers:
+- |-
+  apiVersion: builtin
+  kind: DefaultResourceQuotaTransformer
+  metadata:
+    name: quota
+  hard:
+    requests.cpu: "2"
+`)
+	m := th.Run(".", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: team
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: default-quota
+  namespace: team
+spec:
+  hard:
+    requests.cpu: "2"
+`)
+}
@@ -144,3 +144,55 @@ secretGenerator:
 		t.Errorf("unexpected secret resource name: %s", secret.GetName())
 	}
 }
+
+func TestDisableNameSuffixHashBuildOption(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app/base", `
+generatorOptions:
+  disableNameSuffixHash: false
+configMapGenerator:
+- name: base-config
+  literals:
+  - a=b
+`)
+	th.WriteK("/app/overlay", `
+resources:
+- ../base
+configMapGenerator:
+- name: overlay-config
+  options:
+    disableNameSuffixHash: false
+  literals:
+  - c=d
+secretGenerator:
+- name: overlay-secret
+  literals:
+  - e=f
+`)
+	opts := th.MakeDefaultOptions()
+	opts.DisableNameSuffixHash = true
+	m := th.Run("/app/overlay", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  a: b
+kind: ConfigMap
+metadata:
+  name: base-config
+---
+apiVersion: v1
+data:
+  c: d
+kind: ConfigMap
+metadata:
+  name: overlay-config
+---
+apiVersion: v1
+data:
+  e: Zg==
+kind: Secret
+metadata:
+  name: overlay-secret
+type: Opaque
+`)
+}
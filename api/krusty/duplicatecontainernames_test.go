@@ -0,0 +1,121 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeDeploymentWithSidecar(th kusttest_test.Harness) {
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: web
+        image: nginx
+`)
+}
+
+func TestPatchIntroducingDuplicateContainerName(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeDeploymentWithSidecar(th)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patches:
+- target:
+    kind: Deployment
+    name: web
+  patch: |-
+    - op: add
+      path: /spec/template/spec/containers/-
+      value:
+        name: web
+        image: nginx:1.21
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"has more than one container named 'web' in spec.template.spec") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPatchIntroducingContainerNamedLikeInitContainer(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeDeploymentWithSidecar(th)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+- |-
+  apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+  spec:
+    template:
+      spec:
+        containers:
+        - name: init
+          image: busybox
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "more than one container named 'init'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPatchMergingContainerByName(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeDeploymentWithSidecar(th)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+- |-
+  apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: web
+  spec:
+    template:
+      spec:
+        containers:
+        - name: web
+          image: nginx:1.21
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx:1.21
+        name: web
+      initContainers:
+      - image: busybox
+        name: init
+`)
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// identity is what tells a resource apart in a cluster; unlike
+// a ResId, it leaves out the version, since the API server
+// serves one object under every version of its kind.
+type identity struct {
+	group, kind, namespace, name string
+}
+
+// errIfDuplicateIdentities returns an error listing each set
+// of resources that share an identity, as applying them would
+// leave only the last of each set.
+func errIfDuplicateIdentities(m resmap.ResMap) error {
+	var order []identity
+	sets := make(map[identity][]resid.ResId)
+	for _, r := range m.Resources() {
+		id := r.CurId()
+		key := identity{
+			group:     id.Group,
+			kind:      id.Kind,
+			namespace: id.EffectiveNamespace(),
+			name:      id.Name,
+		}
+		if _, ok := sets[key]; !ok {
+			order = append(order, key)
+		}
+		sets[key] = append(sets[key], id)
+	}
+	var lines []string
+	for _, key := range order {
+		if ids := sets[key]; len(ids) > 1 {
+			var s []string
+			for _, id := range ids {
+				s = append(s, id.String())
+			}
+			lines = append(lines, "  "+strings.Join(s, ", "))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return fmt.Errorf(
+		"resources share a group, kind, namespace and name:\n%s",
+		strings.Join(lines, "\n"))
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestDuplicateIdentities(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/services.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: b
+`)
+	th.WriteK("/app", `
+resources:
+- services.yaml
+patchesJson6902:
+- target:
+    version: v1
+    kind: Service
+    name: web
+    namespace: a
+  patch: |-
+    - op: replace
+      path: /metadata/namespace
+      value: b
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"resources share a group, kind, namespace and name:\n"+
+			"  ~G_v1_Service|b|web, ~G_v1_Service|b|web") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -1213,3 +1213,98 @@ spec:
     app: busybox
 `)
 }
+
+func TestExtendedPatchAnnotationSelector(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("base/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: enabled
+  annotations:
+    feature/x: enabled
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: disabled
+  annotations:
+    feature/x: disabled
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: unflagged
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: enabled
+  annotations:
+    feature/x: enabled
+`)
+	th.WriteK("base", `
+resources:
+- resources.yaml
+patches:
+- target:
+    kind: Deployment
+    annotationSelector: feature/x=enabled
+  patch: |-
+    - op: replace
+      path: /spec/replicas
+      value: 2
+- target:
+    name: enabled
+    annotationSelector: feature/x
+  patch: |-
+    - op: add
+      path: /metadata/labels
+      value:
+        flagged: "true"
+`)
+	m := th.Run("base", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    feature/x: enabled
+  labels:
+    flagged: "true"
+  name: enabled
+spec:
+  replicas: 2
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    feature/x: disabled
+  name: disabled
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: unflagged
+spec:
+  replicas: 1
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    feature/x: enabled
+  labels:
+    flagged: "true"
+  name: enabled
+`)
+}
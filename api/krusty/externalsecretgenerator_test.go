@@ -0,0 +1,67 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestExternalSecretGenerator(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+namespace: shop
+externalSecretGenerator:
+- name: api-keys
+  secretStoreRef:
+    name: vault
+  data:
+  - secretKey: stripe
+    remoteKey: payments/stripe
+  - secretKey: sendgrid
+    remoteKey: mail/sendgrid
+    property: key
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: external-secrets.io/v1
+kind: ExternalSecret
+metadata:
+  name: api-keys
+  namespace: shop
+spec:
+  data:
+  - remoteRef:
+      key: payments/stripe
+    secretKey: stripe
+  - remoteRef:
+      key: mail/sendgrid
+      property: key
+    secretKey: sendgrid
+  secretStoreRef:
+    name: vault
+  target:
+    name: api-keys
+`)
+}
+
+func TestExternalSecretGeneratorNoStore(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+externalSecretGenerator:
+- name: api-keys
+  data:
+  - secretKey: stripe
+    remoteKey: payments/stripe
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "must specify the name of the secretStoreRef") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
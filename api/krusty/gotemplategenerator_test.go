@@ -0,0 +1,89 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestGoTemplateGenerator(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/web.yaml.tmpl", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .name }}
+spec:
+  replicas: {{ .replicas }}
+  template:
+    spec:
+      containers:
+      - name: {{ .name }}
+        image: {{ .image }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .name }}
+spec:
+  ports:
+  - port: {{ .port }}
+`)
+	th.WriteK("/app", `
+namePrefix: shop-
+goTemplateGenerator:
+- template: web.yaml.tmpl
+  values:
+    name: web
+    replicas: 2
+    image: nginx:1.21
+    port: 80
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: shop-web
+spec:
+  replicas: 2
+  template:
+    spec:
+      containers:
+      - image: nginx:1.21
+        name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: shop-web
+spec:
+  ports:
+  - port: 80
+`)
+}
+
+func TestGoTemplateGeneratorMissingValue(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("/app/cm.yaml.tmpl", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .name }}
+`)
+	th.WriteK("/app", `
+goTemplateGenerator:
+- template: cm.yaml.tmpl
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(), "executing template 'cm.yaml.tmpl'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/api/types"
+)
+
+// stubHelmInflater renders every chart to fixed manifests.
+type stubHelmInflater struct {
+	manifests map[string]string
+}
+
+func (s stubHelmInflater) Inflate(chart types.HelmChartArgs) ([]byte, error) {
+	m, ok := s.manifests[chart.ChartName]
+	if !ok {
+		return nil, fmt.Errorf("chart not found in %s", chart.ChartRepoURL)
+	}
+	return []byte(strings.ReplaceAll(
+		m, "RELEASE", chart.ReleaseName)), nil
+}
+
+func TestHelmCharts(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+helmChartInflationGenerator:
+- chartName: redis
+  chartRepoUrl: https://charts.example.com
+  releaseName: cache
+  valuesLocal:
+    replicas: 1
+commonLabels:
+  team: storage
+`)
+	opts := th.MakeDefaultOptions()
+	opts.HelmInflater = stubHelmInflater{manifests: map[string]string{
+		"redis": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: RELEASE-redis
+spec:
+  ports:
+  - port: 6379
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: RELEASE-redis
+spec:
+  serviceName: RELEASE-redis
+`,
+	}}
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    team: storage
+  name: cache-redis
+spec:
+  ports:
+  - port: 6379
+  selector:
+    team: storage
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  labels:
+    team: storage
+  name: cache-redis
+spec:
+  selector:
+    matchLabels:
+      team: storage
+  serviceName: cache-redis
+  template:
+    metadata:
+      labels:
+        team: storage
+`)
+}
+
+func TestHelmChartsInflaterError(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+helmChartInflationGenerator:
+- chartName: missing
+  chartRepoUrl: https://charts.example.com
+`)
+	opts := th.MakeDefaultOptions()
+	opts.HelmInflater = stubHelmInflater{}
+	err := th.RunWithErr("/app", opts)
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, err.Error(),
+		"inflating helm chart 'missing': chart not found in https://charts.example.com")
+}
+
+func TestHelmChartsDisallowed(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+helmChartInflationGenerator:
+- chartName: redis
+  chartRepoUrl: https://charts.example.com
+`)
+	opts := th.MakeDefaultOptions()
+	opts.HelmInflater = stubHelmInflater{manifests: map[string]string{
+		"redis": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: redis
+`,
+	}}
+	opts.AllowedPlugins = []string{"ConfigMapGenerator"}
+	err := th.RunWithErr("/app", opts)
+	if !assert.Error(t, err) {
+		t.FailNow()
+	}
+	assert.Contains(t, err.Error(),
+		"builtin plugin HelmChartInflationGenerator isn't allowed in this build")
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeJsonArrayServiceTemplate(th kusttest_test.Harness) {
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: "{{ .name }}"
+  labels:
+    tier: "{{ .tier }}"
+spec:
+  selector:
+    app: "{{ .name }}"
+  ports:
+  - name: "{{ .name }}-http"
+    port: "{{ .port }}"
+`)
+}
+
+func TestJsonArrayGenerator(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeJsonArrayServiceTemplate(th)
+	th.WriteF("/app/services.json", `[
+  {"name": "frontend", "tier": "web", "port": 80},
+  {"name": "backend", "tier": "api", "port": 8080}
+]`)
+	th.WriteK("/app", `
+namePrefix: shop-
+jsonArrayGenerator:
+- source: services.json
+  template: service.yaml
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    tier: web
+  name: shop-frontend
+spec:
+  ports:
+  - name: frontend-http
+    port: 80
+  selector:
+    app: frontend
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    tier: api
+  name: shop-backend
+spec:
+  ports:
+  - name: backend-http
+    port: 8080
+  selector:
+    app: backend
+`)
+}
+
+func TestJsonArrayGeneratorNotAnArray(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeJsonArrayServiceTemplate(th)
+	th.WriteF("/app/services.json", `{"name": "frontend"}`)
+	th.WriteK("/app", `
+jsonArrayGenerator:
+- source: services.json
+  template: service.yaml
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"source 'services.json' must hold a JSON array") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestJsonArrayGeneratorMissingField(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeJsonArrayServiceTemplate(th)
+	th.WriteF("/app/services.json", `[
+  {"name": "frontend", "tier": "web", "port": 80},
+  {"name": "backend", "port": 8080}
+]`)
+	th.WriteK("/app", `
+jsonArrayGenerator:
+- source: services.json
+  template: service.yaml
+`)
+	err := th.RunWithErr("/app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"element 1 of 'services.json': field 'tier' not found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
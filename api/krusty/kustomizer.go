@@ -5,7 +5,6 @@ package krusty
 
 import (
 	"fmt"
-	"path/filepath"
 
 	"sigs.k8s.io/kustomize/api/builtins"
 	"sigs.k8s.io/kustomize/api/filesys"
@@ -52,6 +51,22 @@ func MakeKustomizer(o *Options) *Kustomizer {
 // and Run can be called on each of them).
 func (b *Kustomizer) Run(
 	fSys filesys.FileSystem, path string) (resmap.ResMap, error) {
+	m, _, err := b.run(fSys, path, false)
+	return m, err
+}
+
+// RunWithBuildMetadata is like Run, but also returns metadata
+// about the build, e.g. which transformers changed each resource,
+// and which resources refer to which others.
+func (b *Kustomizer) RunWithBuildMetadata(
+	fSys filesys.FileSystem, path string) (
+	resmap.ResMap, *types.BuildMetadata, error) {
+	return b.run(fSys, path, true)
+}
+
+func (b *Kustomizer) run(
+	fSys filesys.FileSystem, path string, withMetadata bool) (
+	resmap.ResMap, *types.BuildMetadata, error) {
 	resmapFactory := resmap.NewFactory(
 		b.depProvider.GetResourceFactory(),
 		b.depProvider.GetConflictDetectorFactory())
@@ -59,9 +74,10 @@ func (b *Kustomizer) Run(
 	if b.options.LoadRestrictions == types.LoadRestrictionsRootOnly {
 		lr = fLdr.RestrictionRootOnly
 	}
-	ldr, err := fLdr.NewLoader(lr, path, fSys)
+	ldr, err := fLdr.NewLoaderWithOciPuller(
+		lr, path, fSys, b.options.OciPuller)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer ldr.Cleanup()
 	kt := target.NewKustTarget(
@@ -70,25 +86,45 @@ func (b *Kustomizer) Run(
 		resmapFactory,
 		pLdr.NewLoader(b.options.PluginConfig, resmapFactory),
 	)
+	kt.SetBuildOptions(target.BuildOptions{
+		DisableNameSuffixHash:   b.options.DisableNameSuffixHash,
+		Profile:                 b.options.Profile,
+		RecordTransformations:   withMetadata,
+		RecordDependencies:      withMetadata,
+		RecordPatchChanges:      withMetadata,
+		RecordProvenance:        b.options.AddProvenanceAnnotation,
+		BuildOnlyAnnotation:     b.options.BuildOnlyAnnotation,
+		ImageTagsFromEnv:        b.options.ImageTagsFromEnv,
+		BuildTime:               b.options.BuildTime,
+		WarnOnNoOpPatches:       b.options.WarnOnNoOpPatches,
+		LengthenHashOnCollision: b.options.LengthenHashOnCollision,
+		RequireImageDigests:     b.options.RequireImageDigests,
+		SortContainerEnv:        b.options.SortContainerEnv,
+		AllowedPlugins:          b.options.AllowedPlugins,
+		MaxBaseDepth:            b.options.MaxBaseDepth,
+	})
+	kt.SetHelmInflater(b.options.HelmInflater)
+	kt.SetBuildHooks(b.options.BuildHooks)
 	err = kt.Load()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	var bytes []byte
-	if openApiPath, exists := kt.Kustomization().OpenAPI["path"]; exists {
-		bytes, err = ldr.Load(filepath.Join(ldr.Root(), openApiPath))
-		if err != nil {
-			return nil, err
-		}
+	bytes, err := kt.OpenAPISchema()
+	if err != nil {
+		return nil, nil, err
 	}
 	err = openapi.SetSchema(kt.Kustomization().OpenAPI, bytes, true)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	var m resmap.ResMap
 	m, err = kt.MakeCustomizedResMap()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	err = errIfDuplicateIdentities(m)
+	if err != nil {
+		return nil, nil, err
 	}
 	if b.options.DoLegacyResourceSort {
 		builtins.NewLegacyOrderTransformerPlugin().Transform(m)
@@ -105,6 +141,49 @@ func (b *Kustomizer) Run(
 		}
 		t.Transform(m)
 	}
-	m.RemoveBuildAnnotations()
-	return m, nil
+	if !b.options.KeepBuildAnnotations {
+		m.RemoveBuildAnnotations()
+		removeMetadataKeys(m, b.options.BuildMetadataKeys)
+	}
+	if b.options.SecretDataWidth > 0 {
+		err = wrapSecretData(m, b.options.SecretDataWidth)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	md := kt.BuildMetadata()
+	if b.options.PreviousBuild != nil {
+		m, md.Deleted, err = changesSince(b.options.PreviousBuild, m)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return m, &md, nil
+}
+
+// removeMetadataKeys removes the labels and annotations
+// with the given keys from the resources.
+func removeMetadataKeys(m resmap.ResMap, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	for _, r := range m.Resources() {
+		if labels, removed := withoutKeys(r.GetLabels(), keys); removed {
+			r.SetLabels(labels)
+		}
+		if annotations, removed := withoutKeys(r.GetAnnotations(), keys); removed {
+			r.SetAnnotations(annotations)
+		}
+	}
+}
+
+func withoutKeys(m map[string]string, keys []string) (map[string]string, bool) {
+	removed := false
+	for _, k := range keys {
+		if _, ok := m[k]; ok {
+			delete(m, k)
+			removed = true
+		}
+	}
+	return m, removed
 }
@@ -0,0 +1,108 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeProfiledNamespaceApp(th kusttest_test.Harness) {
+	th.WriteK("app", `
+namespace: team
+profileNamespaces:
+  dev: team-dev
+  prod: team-prod
+resources:
+- resources.yaml
+images:
+- name: web
+  newTag: "1.3"
+  profile: stage
+`)
+	th.WriteF("app/resources.yaml", `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+  - name: web
+    image: web
+`)
+}
+
+func TestNamespaceProfile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeProfiledNamespaceApp(th)
+	opts := th.MakeDefaultOptions()
+	opts.Profile = "prod"
+	m := th.Run("app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: team-prod
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: team-prod
+spec:
+  containers:
+  - image: web
+    name: web
+`)
+}
+
+func TestNamespaceProfileFallsBackToNamespace(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeProfiledNamespaceApp(th)
+	// Known through the images, but without a namespace of its own.
+	opts := th.MakeDefaultOptions()
+	opts.Profile = "stage"
+	m := th.Run("app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: team
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  namespace: team
+spec:
+  containers:
+  - image: web:1.3
+    name: web
+`)
+}
+
+func TestNamespaceUnknownProfile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeProfiledNamespaceApp(th)
+	opts := th.MakeDefaultOptions()
+	opts.Profile = "qa"
+	err := th.RunWithErr("app", opts)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"unknown namespace profile 'qa'") ||
+		!strings.Contains(err.Error(), "[dev prod stage]") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
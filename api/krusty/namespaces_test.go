@@ -379,7 +379,7 @@ subjects:
   namespace: random
 - kind: ServiceAccount
   name: default
-  namespace: newnamespace
+  namespace: irrelevant
 ---
 apiVersion: admissionregistration.k8s.io/v1
 kind: ValidatingWebhookConfiguration
@@ -419,7 +419,7 @@ metadata:
 subjects:
 - kind: ServiceAccount
   name: default
-  namespace: newnamespace
+  namespace: irrelevant
 ---
 apiVersion: v1
 kind: PersistentVolume
@@ -774,3 +774,69 @@ metadata:
   namespace: iter8-monitoring
 `)
 }
+
+func TestNamespaceLeavesExternalSubjects(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+namespace: prod
+resources:
+- resources.yaml
+`)
+	th.WriteF("resources.yaml", `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: view
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: view
+subjects:
+- kind: ServiceAccount
+  name: app
+  namespace: default
+- kind: ServiceAccount
+  name: app
+  namespace: monitoring
+- kind: ServiceAccount
+  name: default
+  namespace: kube-system
+- kind: ServiceAccount
+  name: default
+`)
+	m := th.Run(".", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+  namespace: prod
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  name: view
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: view
+subjects:
+- kind: ServiceAccount
+  name: app
+  namespace: prod
+- kind: ServiceAccount
+  name: app
+  namespace: monitoring
+- kind: ServiceAccount
+  name: default
+  namespace: kube-system
+- kind: ServiceAccount
+  name: default
+  namespace: prod
+`)
+}
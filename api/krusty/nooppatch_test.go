@@ -0,0 +1,157 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func writeNoOpPatchApp(th kusttest_test.Harness) {
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patchesStrategicMerge:
+- stale.yaml
+- replicas.yaml
+patchesJson6902:
+- target:
+    group: apps
+    version: v1
+    kind: Deployment
+    name: web
+  patch: |-
+    - op: replace
+      path: /spec/template/spec/containers/0/image
+      value: web:1.0
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1.0
+`)
+	th.WriteF("/app/stale.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/replicas.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+}
+
+func TestWarnOnNoOpPatches(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeNoOpPatchApp(th)
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	opts := th.MakeDefaultOptions()
+	opts.WarnOnNoOpPatches = true
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - image: web:1.0
+        name: web
+`)
+	assert.Contains(t, buf.String(),
+		"patch stale.yaml left apps_v1_Deployment|~X|web unchanged")
+	assert.NotContains(t, buf.String(), "patch replicas.yaml")
+	assert.Contains(t, buf.String(),
+		"patch inline patch left apps_v1_Deployment|~X|web unchanged")
+}
+
+func TestNoOpPatchesNotReportedByDefault(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeNoOpPatchApp(th)
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	th.Run("/app", th.MakeDefaultOptions())
+	assert.NotContains(t, buf.String(), "unchanged")
+}
+
+func TestWarnOnNoOpPatchesField(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+patches:
+- path: stale.yaml
+- target:
+    kind: Deployment
+  patch: |-
+    - op: replace
+      path: /spec/replicas
+      value: 3
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteF("/app/stale.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	opts := th.MakeDefaultOptions()
+	opts.WarnOnNoOpPatches = true
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	assert.Contains(t, buf.String(),
+		"patch stale.yaml left apps_v1_Deployment|~X|web unchanged")
+	assert.NotContains(t, buf.String(), "patch inline patch")
+}
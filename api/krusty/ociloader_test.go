@@ -0,0 +1,156 @@
+// +build integration
+
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+// Run with -tags integration.
+
+package krusty_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/kustomize/kyaml/openapi"
+)
+
+// stubOciPuller serves artifacts from memory, by reference.
+type stubOciPuller map[string]map[string][]byte
+
+func (p stubOciPuller) Pull(ref string) (map[string][]byte, error) {
+	files, ok := p[ref]
+	if !ok {
+		return nil, fmt.Errorf("manifest unknown")
+	}
+	return files, nil
+}
+
+var configBundle = stubOciPuller{
+	"registry.example.com/config/bundle:v1": {
+		"base/kustomization.yaml": []byte(`
+resources:
+- deploy.yaml
+configMapGenerator:
+- name: settings
+  files:
+  - app.properties
+`),
+		"base/deploy.yaml": []byte(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`),
+		"base/app.properties": []byte("mode=fast\n"),
+		"overlays/prod/kustomization.yaml": []byte(`
+namePrefix: prod-
+resources:
+- ../../base
+`),
+	},
+}
+
+func TestBuildFromOciArtifact(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	opts := th.MakeDefaultOptions()
+	opts.OciPuller = configBundle
+	m := th.Run("oci://registry.example.com/config/bundle:v1//overlays/prod", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: prod-web
+---
+apiVersion: v1
+data:
+  app.properties: |
+    mode=fast
+kind: ConfigMap
+metadata:
+  name: prod-settings-fgtbc74498
+`)
+}
+
+func TestBuildFromOciBaseOfLocalOverlay(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+namePrefix: dev-
+resources:
+- oci://registry.example.com/config/bundle:v1//base
+`)
+	opts := th.MakeDefaultOptions()
+	opts.OciPuller = configBundle
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dev-web
+---
+apiVersion: v1
+data:
+  app.properties: |
+    mode=fast
+kind: ConfigMap
+metadata:
+  name: dev-settings-fgtbc74498
+`)
+}
+
+func TestBuildFromMissingOciArtifact(t *testing.T) {
+	opts := krusty.MakeDefaultOptions()
+	opts.OciPuller = configBundle
+	_, err := krusty.MakeKustomizer(opts).Run(filesys.MakeFsInMemory(),
+		"oci://registry.example.com/config/bundle:v2")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"pulling OCI artifact 'registry.example.com/config/bundle:v2': manifest unknown") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOpenApiSchemaInOciArtifact(t *testing.T) {
+	schema, err := ioutil.ReadFile("testdata/customschema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	puller := stubOciPuller{
+		"registry.example.com/app:v1": {
+			"kustomization.yaml": []byte(`
+resources:
+- mycrd.yaml
+openapi:
+  path: schema.json
+`),
+			"mycrd.yaml": []byte(`
+apiVersion: example.com/v1alpha1
+kind: MyCRD
+metadata:
+  name: service
+`),
+			"schema.json": schema,
+		},
+	}
+	th := kusttest_test.MakeHarness(t)
+	opts := th.MakeDefaultOptions()
+	opts.OciPuller = puller
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+	m := th.Run("oci://registry.example.com/app:v1", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1alpha1
+kind: MyCRD
+metadata:
+  name: service
+`)
+	assert.Equal(t, "using custom schema from file provided",
+		openapi.GetSchemaVersion())
+}
@@ -228,3 +228,108 @@ func TestCustomOpenAPIFieldFromComponent(t *testing.T) {
 	th.Run("prod", th.MakeDefaultOptions())
 	assert.Equal(t, "using custom schema from file provided", openapi.GetSchemaVersion())
 }
+
+func TestCustomOpenApiFieldMalformedSchema(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- mycrd.yaml
+openapi:
+  path: mycrd_schema.json
+`)
+	writeCustomResource(th, "mycrd.yaml")
+	th.WriteF("mycrd_schema.json", `{"definitions": [}`)
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+	err := th.RunWithErr(".", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	assert.Contains(t, err.Error(),
+		"malformed OpenAPI schema 'mycrd_schema.json' in '/'")
+}
+
+const gatewaySchema = `{
+  "definitions": {
+    "v1.Gateway": {
+      "properties": {
+        "apiVersion": {"type": "string"},
+        "kind": {"type": "string"},
+        "metadata": {"type": "object"},
+        "spec": {
+          "properties": {
+            "listeners": {
+              "items": {"$ref": "#/definitions/v1.Listener"},
+              "type": "array",
+              "x-kubernetes-patch-merge-key": "port",
+              "x-kubernetes-patch-strategy": "merge"
+            }
+          },
+          "type": "object"
+        }
+      },
+      "type": "object",
+      "x-kubernetes-group-version-kind": [
+        {"group": "example.com", "kind": "Gateway", "version": "v1"}
+      ]
+    },
+    "v1.Listener": {
+      "properties": {
+        "hostname": {"type": "string"},
+        "port": {"type": "integer"},
+        "protocol": {"type": "string"}
+      },
+      "type": "object"
+    }
+  }
+}`
+
+func TestCustomOpenApiFieldMergeKey(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+resources:
+- gateway.yaml
+openapi:
+  path: gateway_schema.json
+patchesStrategicMerge:
+- |-
+  apiVersion: example.com/v1
+  kind: Gateway
+  metadata:
+    name: gateway
+  spec:
+    listeners:
+    - port: 443
+      hostname: shop.example.com
+`)
+	th.WriteF("gateway.yaml", `
+apiVersion: example.com/v1
+kind: Gateway
+metadata:
+  name: gateway
+spec:
+  listeners:
+  - port: 80
+    protocol: HTTP
+  - port: 443
+    protocol: HTTPS
+`)
+	th.WriteF("gateway_schema.json", gatewaySchema)
+	openapi.ResetOpenAPI()
+	defer openapi.ResetOpenAPI()
+	m := th.Run(".", th.MakeDefaultOptions())
+	// Merged by port, instead of replacing the list.
+	th.AssertActualEqualsExpected(m, `
+apiVersion: example.com/v1
+kind: Gateway
+metadata:
+  name: gateway
+spec:
+  listeners:
+  - hostname: shop.example.com
+    port: 443
+    protocol: HTTPS
+  - port: 80
+    protocol: HTTP
+`)
+}
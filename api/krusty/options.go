@@ -4,8 +4,12 @@
 package krusty
 
 import (
+	"time"
+
+	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/internal/plugins/builtinhelpers"
 	"sigs.k8s.io/kustomize/api/konfig"
+	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
 )
 
@@ -24,6 +28,13 @@ type Options struct {
 	// is added to all the resources in the build out.
 	AddManagedbyLabel bool
 
+	// When true, every resource of the build output that came
+	// from a base, or that a transformer changed, is annotated
+	// with the ordered list of those bases and transformers,
+	// noting the component each transformer belongs to, if
+	// any; see konfig.ProvenanceAnnotation.
+	AddProvenanceAnnotation bool
+
 	// Restrictions on what can be loaded from the file system.
 	// See type definition.
 	LoadRestrictions types.LoadRestrictions
@@ -42,6 +53,108 @@ type Options struct {
 	// When true, allow name and kind changing via a patch
 	// When false, patch name/kind don't overwrite target name/kind
 	AllowResourceIdChanges bool
+
+	// When true, no builtin generator appends a content hash
+	// to the names of the ConfigMaps and Secrets it makes,
+	// regardless of any generatorOptions in the kustomization
+	// files.  Useful when something else owns the naming.
+	DisableNameSuffixHash bool
+
+	// Profile selects a build profile, e.g. "prod".  Kustomization
+	// entries assigned to a profile, such as image entries, only
+	// apply when their profile is the one selected here.
+	Profile string
+
+	// BuildOnlyAnnotation, if not empty, is an annotation key
+	// marking resources that shouldn't be emitted, e.g. because
+	// they only hold data for vars.  A value of "false" keeps
+	// the resource.  The annotation itself is never emitted.
+	BuildOnlyAnnotation string
+
+	// When true, the annotations kustomize puts on resources
+	// for its own use during the build, e.g. to remember their
+	// previous names, are emitted, as are BuildMetadataKeys.
+	// Useful for debugging a build.
+	KeepBuildAnnotations bool
+
+	// BuildMetadataKeys lists more label and annotation keys
+	// that only matter during the build, e.g. markers that
+	// patch targets select on.  Unless KeepBuildAnnotations
+	// is true, they're removed from the resources emitted.
+	BuildMetadataKeys []string
+
+	// When true, image entries in kustomization files may use
+	// newTagEnv to read their new tag from the environment.
+	// Otherwise newTagEnv is an error.
+	ImageTagsFromEnv bool
+
+	// BuildTime, if not zero, is used instead of the current
+	// time for every timestamp the build emits, so that builds
+	// of the same input are reproducible.
+	BuildTime time.Time
+
+	// When true, patches, patchesStrategicMerge and patchesJson6902
+	// entries log a warning for each target they leave unchanged, which
+	// helps to find patches that no longer do anything.
+	WarnOnNoOpPatches bool
+
+	// When true, generated ConfigMaps and Secrets of different
+	// content that end up with the same name, as their hash
+	// suffixes are truncated, get suffixes long enough to tell
+	// them apart, rather than failing the build.
+	LengthenHashOnCollision bool
+
+	// When true, the build fails, listing the offenders, if any
+	// container image of its output lacks a sha256 digest, after
+	// the digests of images entries are set.
+	RequireImageDigests bool
+
+	// When true, the env vars of every container of the output
+	// are sorted by name, so that their order doesn't depend on
+	// that of the patches and transformers that added them.  A
+	// var whose value refers to another, e.g. as $(HOST), keeps
+	// its place relative to that one, and a container holding
+	// two vars of the same name is left as is.
+	SortContainerEnv bool
+
+	// AllowedPlugins, if not nil, names the only builtin
+	// plugins, e.g. "ConfigMapGenerator", that the build may
+	// run, e.g. when building untrusted kustomizations.  The
+	// build fails before running any plugin if it needs others.
+	AllowedPlugins []string
+
+	// MaxBaseDepth, if not zero, is how deep bases and components
+	// may be included, e.g. to guard a shared build service from
+	// runaway chains of bases; the bases of the kustomization
+	// built are at depth one.  Zero means a limit of 100.
+	MaxBaseDepth int
+
+	// SecretDataWidth, if greater than zero, is the width at
+	// which the base64 encoded values of the data of Secrets are
+	// wrapped, using literal block scalars, for readable diffs.
+	// This changes neither the decoded data, nor the name suffix
+	// hashes, which are computed before.
+	SecretDataWidth int
+
+	// PreviousBuild, if not nil, holds the output of an earlier
+	// build.  The build then only emits the resources that are
+	// new or whose content changed since, e.g. for incremental
+	// applies.  The ids of the resources that the earlier build
+	// emitted but this one doesn't are the Deleted of the build
+	// metadata.
+	PreviousBuild resmap.ResMap
+
+	// HelmInflater, if not nil, renders the helmChartInflationGenerator
+	// entries of kustomization files, rather than the helm binary.
+	HelmInflater ifc.HelmInflater
+
+	// BuildHooks, if not nil, observe the phases of the build,
+	// e.g. to time each transformer.
+	BuildHooks ifc.BuildHooks
+
+	// OciPuller, if not nil, pulls the OCI artifacts that the
+	// kustomization path or bases refer to with oci:// references.
+	OciPuller ifc.OciPuller
 }
 
 // MakeDefaultOptions returns a default instance of Options.
@@ -54,6 +167,7 @@ func MakeDefaultOptions() *Options {
 		PluginConfig:           konfig.DisabledPluginConfig(),
 		UseKyaml:               konfig.FlagEnableKyamlDefaultValue,
 		AllowResourceIdChanges: false,
+		DisableNameSuffixHash:  false,
 	}
 }
 
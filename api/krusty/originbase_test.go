@@ -0,0 +1,98 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestTransformerScopedToOriginBase(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t)
+	defer th.Reset()
+
+	th.WriteK("bases/a", `
+resources:
+- service.yaml
+- nested
+`)
+	th.WriteF("bases/a/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: a
+`)
+	th.WriteK("bases/a/nested", `
+resources:
+- service.yaml
+`)
+	th.WriteF("bases/a/nested/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: a-nested
+`)
+	th.WriteK("bases/b", `
+resources:
+- service.yaml
+`)
+	th.WriteF("bases/b/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+`)
+	th.WriteF("service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: top
+`)
+	th.WriteK(".", `
+resources:
+- ./bases/a/
+- bases/b
+- service.yaml
+transformers:
+- |-
+  apiVersion: builtin
+  kind: LabelTransformer
+  metadata:
+    name: not-important-to-example
+    annotations:
+      config.kubernetes.io/origin-base: bases/a
+  labels:
+    team: a
+  fieldSpecs:
+  - path: metadata/labels
+    create: true
+`)
+	m := th.Run(".", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    team: a
+  name: a
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    team: a
+  name: a-nested
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: b
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: top
+`)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestAllowedPlugins(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app/configs", `
+configMapGenerator:
+- name: settings
+  literals:
+  - mode=fast
+`)
+	th.WriteK("/app/secrets", `
+secretGenerator:
+- name: token
+  literals:
+  - token=abc
+`)
+	th.WriteK("/app/inline", `
+generators:
+- |-
+  apiVersion: builtin
+  kind: SecretGenerator
+  metadata:
+    name: token
+  literals:
+  - token=abc
+`)
+	opts := th.MakeDefaultOptions()
+	opts.AllowedPlugins = []string{"ConfigMapGenerator"}
+	m := th.Run("/app/configs", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  name: settings-t82mkhg8fd
+`)
+	for _, dir := range []string{"/app/secrets", "/app/inline"} {
+		err := th.RunWithErr(dir, opts)
+		if err == nil {
+			t.Fatalf("expected an error building %s", dir)
+		}
+		if !strings.Contains(err.Error(),
+			"builtin plugin SecretGenerator isn't allowed in this build") {
+			t.Fatalf("unexpected error building %s: %v", dir, err)
+		}
+	}
+}
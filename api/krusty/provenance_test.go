@@ -0,0 +1,96 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestProvenanceAnnotation(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/base", `
+resources:
+- deployment.yaml
+`)
+	th.WriteF("/base/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+`)
+	th.WriteC("/components/replicas", `
+patchesStrategicMerge:
+- replicas.yaml
+`)
+	th.WriteF("/components/replicas/replicas.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 3
+`)
+	th.WriteK("/app", `
+resources:
+- ../base
+- service.yaml
+components:
+- ../components/replicas
+namePrefix: prod-
+`)
+	th.WriteF("/app/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	opts := th.MakeDefaultOptions()
+	opts.AddProvenanceAnnotation = true
+	th.AssertActualEqualsExpected(th.Run("/app", opts), `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    kustomize.config.k8s.io/provenance: '[{"base":"../base"},{"component":"../components/replicas","transformer":"PatchStrategicMergeTransformer"},{"transformer":"PrefixSuffixTransformer"}]'
+  name: prod-web
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    kustomize.config.k8s.io/provenance: '[{"transformer":"PrefixSuffixTransformer"}]'
+  name: prod-web
+`)
+}
+
+func TestProvenanceAnnotationNotAsked(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/base", `
+resources:
+- service.yaml
+`)
+	th.WriteF("/base/service.yaml", `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`)
+	th.WriteK("/app", `
+resources:
+- ../base
+namePrefix: prod-
+`)
+	th.AssertActualEqualsExpected(th.Run("/app", th.MakeDefaultOptions()), `
+apiVersion: v1
+kind: Service
+metadata:
+  name: prod-web
+`)
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestRecommendedLabels(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- resources.yaml
+recommendedLabels:
+  name: mysql
+  instance: mysql-abcxzy
+  version: 5.7.21
+  component: database
+  partOf: wordpress
+`)
+	th.WriteF("/app/resources.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: mysql
+spec:
+  template:
+    spec:
+      containers:
+      - name: mysql
+        image: mysql
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: mysql
+spec:
+  ports:
+  - port: 3306
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    app.kubernetes.io/component: database
+    app.kubernetes.io/instance: mysql-abcxzy
+    app.kubernetes.io/name: mysql
+    app.kubernetes.io/part-of: wordpress
+    app.kubernetes.io/version: 5.7.21
+  name: mysql
+spec:
+  selector:
+    matchLabels:
+      app.kubernetes.io/component: database
+      app.kubernetes.io/instance: mysql-abcxzy
+      app.kubernetes.io/name: mysql
+      app.kubernetes.io/part-of: wordpress
+      app.kubernetes.io/version: 5.7.21
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/component: database
+        app.kubernetes.io/instance: mysql-abcxzy
+        app.kubernetes.io/name: mysql
+        app.kubernetes.io/part-of: wordpress
+        app.kubernetes.io/version: 5.7.21
+    spec:
+      containers:
+      - image: mysql
+        name: mysql
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app.kubernetes.io/component: database
+    app.kubernetes.io/instance: mysql-abcxzy
+    app.kubernetes.io/name: mysql
+    app.kubernetes.io/part-of: wordpress
+    app.kubernetes.io/version: 5.7.21
+  name: mysql
+spec:
+  ports:
+  - port: 3306
+  selector:
+    app.kubernetes.io/component: database
+    app.kubernetes.io/instance: mysql-abcxzy
+    app.kubernetes.io/name: mysql
+    app.kubernetes.io/part-of: wordpress
+    app.kubernetes.io/version: 5.7.21
+`)
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty
+
+import (
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+)
+
+// wrapSecretData breaks the values of the data of Secrets,
+// which are base64 encoded, into lines of at most the given
+// width held by literal block scalars.  The Secrets keep their
+// meaning, as base64 decoding skips line breaks.
+func wrapSecretData(m resmap.ResMap, width int) error {
+	for _, r := range m.Resources() {
+		gvk := r.GetGvk()
+		if gvk.Group != "" || gvk.Kind != "Secret" {
+			continue
+		}
+		err := r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				data, err := node.Pipe(yaml.Lookup("data"))
+				if err != nil || data == nil ||
+					data.YNode().Kind != yaml.MappingNode {
+					return node, err
+				}
+				content := data.YNode().Content
+				for i := 1; i < len(content); i += 2 {
+					wrapScalar(content[i], width)
+				}
+				return node, nil
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func wrapScalar(n *yaml.Node, width int) {
+	if n.Kind != yaml.ScalarNode || len(n.Value) <= width {
+		return
+	}
+	var lines []string
+	for v := n.Value; len(v) > 0; {
+		i := width
+		if i > len(v) {
+			i = len(v)
+		}
+		lines = append(lines, v[:i])
+		v = v[i:]
+	}
+	n.Value = strings.Join(lines, "\n")
+	n.Style = yaml.LiteralStyle
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package krusty_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+	"sigs.k8s.io/yaml"
+)
+
+const longSecretValue = "a value long enough to wrap more than once"
+
+func TestSecretDataWidth(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK(".", `
+secretGenerator:
+- name: creds
+  literals:
+  - token=`+longSecretValue+`
+  - short=abc
+`)
+	opts := th.MakeDefaultOptions()
+	opts.SecretDataWidth = 20
+	m := th.Run(".", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: v1
+data:
+  short: YWJj
+  token: |-
+    YSB2YWx1ZSBsb25nIGVu
+    b3VnaCB0byB3cmFwIG1v
+    cmUgdGhhbiBvbmNl
+kind: Secret
+metadata:
+  name: creds-66dhb82k4g
+type: Opaque
+`)
+	// The hash is the one of the unwrapped Secret.
+	unwrapped := th.Run(".", th.MakeDefaultOptions())
+	assert.Equal(t, unwrapped.Resources()[0].GetName(), m.Resources()[0].GetName())
+
+	// The emitted Secret decodes to the original value.
+	out, err := m.AsYaml()
+	require.NoError(t, err)
+	var secret struct {
+		Data map[string][]byte `json:"data"`
+	}
+	require.NoError(t, yaml.Unmarshal(out, &secret))
+	assert.Equal(t, longSecretValue, string(secret.Data["token"]))
+	decoded, err := base64.StdEncoding.DecodeString(
+		m.Resources()[0].GetDataMap()["token"])
+	require.NoError(t, err)
+	assert.Equal(t, longSecretValue, string(decoded))
+}
@@ -4,6 +4,8 @@
 package krusty_test
 
 import (
+	"os"
+	"strings"
 	"testing"
 
 	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
@@ -400,3 +402,431 @@ spec:
             image: solsa-echo:foo
 `)
 }
+
+// Images in any field named containers or initContainers are
+// found without field specs, so those of progressive delivery
+// workloads, like Argo Rollouts, get rewritten too.
+func TestTransfomersImageRollout(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteF("app/rollout.yaml", `
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: web
+spec:
+  strategy:
+    canary:
+      steps:
+      - setWeight: 20
+      - pause: {}
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: web
+      containers:
+      - name: web
+        image: web:1.0
+`)
+	th.WriteK("app", `
+resources:
+- rollout.yaml
+images:
+- name: web
+  newName: registry.example.com/web
+  newTag: "1.1"
+`)
+	m := th.Run("app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: web
+spec:
+  strategy:
+    canary:
+      steps:
+      - setWeight: 20
+      - pause: {}
+  template:
+    spec:
+      containers:
+      - image: registry.example.com/web:1.1
+        name: web
+      initContainers:
+      - image: registry.example.com/web:1.1
+        name: migrate
+`)
+}
+
+func writeProfiledImagesApp(th kusttest_test.Harness) {
+	th.WriteF("app/deploy.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+      - name: proxy
+        image: envoy
+`)
+	th.WriteK("app", `
+resources:
+- deploy.yaml
+images:
+- name: envoy
+  newTag: v1.18
+- name: web
+  newTag: dev-latest
+  profile: dev
+- name: web
+  newTag: 1.4.0-rc1
+  profile: stage
+- name: web
+  newName: registry.example.com/web
+  newTag: 1.3.2
+  profile: prod
+`)
+}
+
+func TestTransformersImageProfile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeProfiledImagesApp(th)
+	opts := th.MakeDefaultOptions()
+	opts.Profile = "prod"
+	m := th.Run("app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: registry.example.com/web:1.3.2
+        name: web
+      - image: envoy:v1.18
+        name: proxy
+`)
+}
+
+func TestTransformersImageNoProfile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeProfiledImagesApp(th)
+	m := th.Run("app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: web
+        name: web
+      - image: envoy:v1.18
+        name: proxy
+`)
+}
+
+func TestTransformersImageUnknownProfile(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeProfiledImagesApp(th)
+	opts := th.MakeDefaultOptions()
+	opts.Profile = "qa"
+	err := th.RunWithErr("app", opts)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"unknown image profile 'qa'") ||
+		!strings.Contains(err.Error(), "[dev prod stage]") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestImageNormalizationAfterImages(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+images:
+- name: nginx
+  newTag: "1.21"
+transformers:
+- |-
+  apiVersion: builtin
+  kind: ImageNormalizationTransformer
+  metadata:
+    name: normalize
+  disallowLatest: true
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: nginx
+        image: nginx
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: docker.io/library/nginx:1.21
+        name: nginx
+`)
+}
+
+func TestImageDigestRotation(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+images:
+- name: app
+  digest: sha256:old
+  newDigest: sha256:new
+`)
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: app@sha256:other
+      containers:
+      - name: app
+        image: app@sha256:old
+`)
+	m := th.Run("/app", th.MakeDefaultOptions())
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: app@sha256:new
+        name: app
+      initContainers:
+      - image: app@sha256:other
+        name: init
+`)
+}
+
+const imageTagEnv = "KUSTOMIZE_TEST_IMAGE_TAG"
+
+func writeImageTagEnvApp(th kusttest_test.Harness, image string) {
+	th.WriteK("app", `
+resources:
+- deploy.yaml
+images:
+`+image)
+	th.WriteF("app/deploy.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web:1.0
+`)
+}
+
+func TestImageNewTagEnv(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeImageTagEnvApp(th, `
+- name: web
+  newTagEnv: `+imageTagEnv+`
+`)
+	os.Setenv(imageTagEnv, "ci-1234")
+	defer os.Unsetenv(imageTagEnv)
+	opts := th.MakeDefaultOptions()
+	opts.ImageTagsFromEnv = true
+	m := th.Run("app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: web:ci-1234
+        name: web
+`)
+
+	err := th.RunWithErr("app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"image tags from the environment aren't enabled") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestImageNewTagEnvUnset(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeImageTagEnvApp(th, `
+- name: web
+  newTagEnv: `+imageTagEnv+`
+`)
+	os.Unsetenv(imageTagEnv)
+	opts := th.MakeDefaultOptions()
+	opts.ImageTagsFromEnv = true
+	err := th.RunWithErr("app", opts)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"environment variable '"+imageTagEnv+"' for the tag of image 'web' is not set") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestImageNewTagEnvWithOtherTagSource(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeImageTagEnvApp(th, `
+- name: web
+  newTag: "2.0"
+  newTagEnv: `+imageTagEnv+`
+`)
+	os.Setenv(imageTagEnv, "ci-1234")
+	defer os.Unsetenv(imageTagEnv)
+	opts := th.MakeDefaultOptions()
+	opts.ImageTagsFromEnv = true
+	err := th.RunWithErr("app", opts)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"has newTagEnv and another of newTag, digest or newDigest") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestImageMalformedNewName(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeImageTagEnvApp(th, `
+- name: web
+  newName: "registry:5000/web:"
+`)
+	err := th.RunWithErr("app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"image 'web' has malformed newName: empty tag or digest after ':'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestImageMalformedDigest(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeImageTagEnvApp(th, `
+- name: web
+  digest: sha256
+`)
+	err := th.RunWithErr("app", th.MakeDefaultOptions())
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"image 'web' has malformed digest: invalid digest 'sha256'") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func writeDigestPinningApp(th kusttest_test.Harness, images string) {
+	th.WriteF("/app/deployment.yaml", `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate@sha256:0ae1a1e9a206cc8b5e3e0bb1a0c7c9b1e6c5d9b8e0c1a4e7a2b6a1f3c2d4e5f6
+      containers:
+      - name: web
+        image: nginx:1.21
+`)
+	th.WriteK("/app", `
+resources:
+- deployment.yaml
+`+images)
+}
+
+func TestRequireImageDigests(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeDigestPinningApp(th, "")
+	opts := th.MakeDefaultOptions()
+	opts.RequireImageDigests = true
+	err := th.RunWithErr("/app", opts)
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if !strings.Contains(err.Error(),
+		"images must be pinned to a sha256 digest:\n"+
+			"  apps_v1_Deployment|~X|web: nginx:1.21") ||
+		strings.Contains(err.Error(), "migrate") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRequireImageDigestsSetByImages(t *testing.T) {
+	th := kusttest_test.MakeHarness(t)
+	writeDigestPinningApp(th, `
+images:
+- name: nginx
+  digest: sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3
+`)
+	opts := th.MakeDefaultOptions()
+	opts.RequireImageDigests = true
+	m := th.Run("/app", opts)
+	th.AssertActualEqualsExpected(m, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx@sha256:24a0c4b4a4c0eb97a1aabb8e29f18e917d05abfe1b7a7c07857230879ce7d3d3
+        name: web
+      initContainers:
+      - image: migrate@sha256:0ae1a1e9a206cc8b5e3e0bb1a0c7c9b1e6c5d9b8e0c1a4e7a2b6a1f3c2d4e5f6
+        name: migrate
+`)
+}
@@ -6,9 +6,11 @@ package kv
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -16,6 +18,7 @@ import (
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
 )
 
 var utf8bom = []byte{0xEF, 0xBB, 0xBF}
@@ -47,19 +50,76 @@ func (kvl *loader) Load(
 	}
 	all = append(all, pairs...)
 
-	pairs, err = keyValuesFromLiteralSources(args.LiteralSources)
+	literals, err := keyValuesFromLiteralSources(args.LiteralSources)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf(
 			"literal sources %v", args.LiteralSources))
 	}
+
+	pairs, err = kvl.keyValuesFromValuesFiles(args.ValuesFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf(
+			"values files: %v", args.ValuesFiles))
+	}
+	if args.StrictValuesFiles {
+		if k := sharedKey(pairs, literals); k != "" {
+			return nil, fmt.Errorf(
+				"key '%s' is in both values files %v and literals, "+
+					"which strictValuesFiles forbids", k, args.ValuesFiles)
+		}
+	} else {
+		pairs = withoutKeysOf(pairs, literals)
+	}
 	all = append(all, pairs...)
+	all = append(all, literals...)
 
 	pairs, err = kvl.keyValuesFromFileSources(args.FileSources)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf(
 			"file sources: %v", args.FileSources))
 	}
-	return append(all, pairs...), nil
+	all = append(all, pairs...)
+
+	for _, name := range args.Transform {
+		fn, ok := valueTransforms[name]
+		if !ok {
+			return nil, fmt.Errorf(
+				"unknown transform '%s'; expected one of %v",
+				name, valueTransformNames())
+		}
+		for i := range all {
+			all[i].Value, err = fn(all[i].Value)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf(
+					"transform %s of key %s", name, all[i].Key))
+			}
+		}
+	}
+	return all, nil
+}
+
+// valueTransforms holds the transformations that can be
+// applied to values, by name.
+var valueTransforms = map[string]func(string) (string, error){
+	"trimSpace": func(v string) (string, error) {
+		return strings.TrimSpace(v), nil
+	},
+	"jsonPretty": func(v string) (string, error) {
+		var b bytes.Buffer
+		if err := json.Indent(&b, []byte(strings.TrimSpace(v)), "", "  "); err != nil {
+			return "", err
+		}
+		return b.String() + "\n", nil
+	},
+}
+
+func valueTransformNames() []string {
+	var result []string
+	for k := range valueTransforms {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
 }
 
 func keyValuesFromLiteralSources(sources []string) ([]types.Pair, error) {
@@ -90,6 +150,64 @@ func (kvl *loader) keyValuesFromFileSources(sources []string) ([]types.Pair, err
 	return kvs, nil
 }
 
+// keyValuesFromValuesFiles reads the pairs of flat YAML maps,
+// in sorted key order per file.
+func (kvl *loader) keyValuesFromValuesFiles(paths []string) ([]types.Pair, error) {
+	var kvs []types.Pair
+	for _, p := range paths {
+		content, err := kvl.ldr.Load(p)
+		if err != nil {
+			return nil, err
+		}
+		var m map[string]interface{}
+		if err = yaml.Unmarshal(content, &m); err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", p)
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			v, ok := m[k].(string)
+			if !ok {
+				return nil, fmt.Errorf(
+					"value of key '%s' in %s is not a string", k, p)
+			}
+			kvs = append(kvs, types.Pair{Key: k, Value: v})
+		}
+	}
+	return kvs, nil
+}
+
+// withoutKeysOf returns the pairs whose keys aren't in others.
+func withoutKeysOf(pairs, others []types.Pair) []types.Pair {
+	drop := make(map[string]bool, len(others))
+	for _, o := range others {
+		drop[o.Key] = true
+	}
+	var result []types.Pair
+	for _, p := range pairs {
+		if !drop[p.Key] {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// sharedKey returns the first key of pairs that is in others
+// too, or the empty string if there's none.
+func sharedKey(pairs, others []types.Pair) string {
+	for _, p := range pairs {
+		for _, o := range others {
+			if p.Key == o.Key {
+				return p.Key
+			}
+		}
+	}
+	return ""
+}
+
 func (kvl *loader) keyValuesFromEnvFiles(paths []string) ([]types.Pair, error) {
 	var kvs []types.Pair
 	for _, p := range paths {
@@ -196,6 +314,22 @@ func parseFileSource(source string) (keyName, filePath string, err error) {
 	}
 }
 
+// SourcePaths returns the paths of the files read by the
+// sources, i.e. the file, env and values file sources.
+// Malformed file sources are left out; loading reports them.
+func SourcePaths(args types.KvPairSources) (result []string) {
+	for _, s := range args.FileSources {
+		if _, p, err := parseFileSource(s); err == nil {
+			result = append(result, p)
+		}
+	}
+	result = append(result, args.EnvSources...)
+	if args.EnvSource != "" {
+		result = append(result, args.EnvSource)
+	}
+	return append(result, args.ValuesFiles...)
+}
+
 // ParseLiteralSource parses the source key=val pair into its component pieces.
 // This functionality is distinguished from strings.SplitN(source, "=", 2) since
 // it returns an error in the case of empty keys, values, or a missing equals sign.
@@ -5,6 +5,7 @@ package kv
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"sigs.k8s.io/kustomize/api/filesys"
@@ -95,3 +96,127 @@ func TestKeyValuesFromFileSources(t *testing.T) {
 		}
 	}
 }
+
+func TestLoadValuesFiles(t *testing.T) {
+	tests := []struct {
+		description string
+		sources     types.KvPairSources
+		expected    []types.Pair
+		expectedErr string
+	}{
+		{
+			description: "literals override values file",
+			sources: types.KvPairSources{
+				ValuesFiles:    []string{"values.yaml"},
+				LiteralSources: []string{"b=literal"},
+			},
+			expected: []types.Pair{
+				{Key: "a", Value: "1"},
+				{Key: "c", Value: "3"},
+				{Key: "b", Value: "literal"},
+			},
+		},
+		{
+			description: "strict rejects both",
+			sources: types.KvPairSources{
+				ValuesFiles:       []string{"values.yaml"},
+				LiteralSources:    []string{"b=literal"},
+				StrictValuesFiles: true,
+			},
+			expectedErr: "key 'b' is in both values files [values.yaml] and literals",
+		},
+		{
+			description: "non-string value",
+			sources: types.KvPairSources{
+				ValuesFiles: []string{"numbers.yaml"},
+			},
+			expectedErr: "value of key 'port' in numbers.yaml is not a string",
+		},
+	}
+
+	fSys := filesys.MakeFsInMemory()
+	fSys.WriteFile("/values.yaml", []byte(`
+c: "3"
+a: "1"
+b: "2"
+`))
+	fSys.WriteFile("/numbers.yaml", []byte(`
+port: 8080
+`))
+	kvl := makeKvLoader(fSys)
+	for _, tc := range tests {
+		kvs, err := kvl.Load(tc.sources)
+		if tc.expectedErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("in testcase: %q expected error %q, got %v",
+					tc.description, tc.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(kvs, tc.expected) {
+			t.Fatalf("in testcase: %q updated:\n%#v\ndoesn't match expected:\n%#v\n", tc.description, kvs, tc.expected)
+		}
+	}
+}
+
+func TestLoadTransform(t *testing.T) {
+	tests := []struct {
+		description string
+		transform   []string
+		content     string
+		expected    string
+		expectedErr string
+	}{
+		{
+			description: "trimSpace",
+			transform:   []string{"trimSpace"},
+			content:     "\n  value  \n\n",
+			expected:    "value",
+		},
+		{
+			description: "jsonPretty",
+			transform:   []string{"jsonPretty"},
+			content:     `{"a":1,"b":[true,null]}`,
+			expected:    "{\n  \"a\": 1,\n  \"b\": [\n    true,\n    null\n  ]\n}\n",
+		},
+		{
+			description: "jsonPretty of invalid json",
+			transform:   []string{"jsonPretty"},
+			content:     `{"a":`,
+			expectedErr: "transform jsonPretty of key data",
+		},
+		{
+			description: "unknown",
+			transform:   []string{"rot13"},
+			content:     "value",
+			expectedErr: "unknown transform 'rot13'; expected one of [jsonPretty trimSpace]",
+		},
+	}
+
+	for _, tc := range tests {
+		fSys := filesys.MakeFsInMemory()
+		fSys.WriteFile("/data", []byte(tc.content))
+		kvl := makeKvLoader(fSys)
+		kvs, err := kvl.Load(types.KvPairSources{
+			FileSources: []string{"data"},
+			Transform:   tc.transform,
+		})
+		if tc.expectedErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("in testcase: %q expected error %q, got %v",
+					tc.description, tc.expectedErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []types.Pair{{Key: "data", Value: tc.expected}}
+		if !reflect.DeepEqual(kvs, expected) {
+			t.Fatalf("in testcase: %q updated:\n%#v\ndoesn't match expected:\n%#v\n", tc.description, kvs, expected)
+		}
+	}
+}
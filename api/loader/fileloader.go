@@ -9,6 +9,7 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 
@@ -95,6 +96,10 @@ type fileLoader struct {
 	// Used to clone repositories.
 	cloner git.Cloner
 
+	// Used to pull OCI artifacts.  Only set on the
+	// first loader; the others ask their referrers.
+	ociPuller ifc.OciPuller
+
 	// Used to clean up, as needed.
 	cleaner func() error
 }
@@ -171,6 +176,10 @@ func (fl *fileLoader) New(path string) (ifc.Loader, error) {
 		return nil, fmt.Errorf("new root cannot be empty")
 	}
 
+	if isOciRef(path) {
+		return newOciLoader(path, nil, fl.fSys, fl.cloner, fl.puller())
+	}
+
 	repoSpec, err := git.NewRepoSpecFromUrl(path)
 	if err == nil {
 		// Treat this as git repo clone request.
@@ -264,6 +273,18 @@ func (fl *fileLoader) containingRepo() *git.RepoSpec {
 	return fl.referrer.containingRepo()
 }
 
+// Looks back through referrers for an OCI puller, returning
+// nil if none found.
+func (fl *fileLoader) puller() ifc.OciPuller {
+	if fl.ociPuller != nil {
+		return fl.ociPuller
+	}
+	if fl.referrer == nil {
+		return nil
+	}
+	return fl.referrer.puller()
+}
+
 // errIfArgEqualOrHigher tests whether the argument,
 // is equal to or above the root of any ancestor.
 func (fl *fileLoader) errIfArgEqualOrHigher(
@@ -330,6 +351,38 @@ func (fl *fileLoader) Load(path string) ([]byte, error) {
 	return fl.fSys.ReadFile(path)
 }
 
+// ListDir returns the sorted names of the directories and of
+// the files in the directory at the given path.  Relative
+// paths are taken relative to the root.
+func (fl *fileLoader) ListDir(path string) (dirs, files []string, err error) {
+	if !filepath.IsAbs(path) {
+		path = fl.root.Join(path)
+	}
+	if !fl.fSys.IsDir(path) {
+		return nil, nil, fmt.Errorf("'%s' must be a directory", path)
+	}
+	self := true
+	err = fl.fSys.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if self {
+			self = false
+			return nil
+		}
+		if info.IsDir() {
+			dirs = append(dirs, filepath.Base(p))
+			return filepath.SkipDir
+		}
+		files = append(files, filepath.Base(p))
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return dirs, files, nil
+}
+
 // Cleanup runs the cleaner.
 func (fl *fileLoader) Cleanup() error {
 	return fl.cleaner()
@@ -672,3 +672,20 @@ func TestLoaderHTTP(t *testing.T) {
 		}
 	}
 }
+
+func TestLoaderListDir(t *testing.T) {
+	l := makeLoader()
+	dirs, files, err := l.ListDir("foo/project")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dirs, []string{"subdir1", "subdir2"}) {
+		t.Fatalf("unexpected dirs: %v", dirs)
+	}
+	if !reflect.DeepEqual(files, []string{"fileA.yaml", "fileD.yaml"}) {
+		t.Fatalf("unexpected files: %v", files)
+	}
+	if _, _, err = l.ListDir("foo/project/fileA.yaml"); err == nil {
+		t.Fatalf("expected an error listing a file")
+	}
+}
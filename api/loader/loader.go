@@ -32,3 +32,22 @@ func NewLoader(
 	return newLoaderAtConfirmedDir(
 		lr, root, fSys, nil, git.ClonerUsingGitExec), nil
 }
+
+// NewLoaderWithOciPuller is like NewLoader, except that the
+// target, and any base, may also be a reference to an OCI
+// artifact, e.g. oci://registry.example.com/bundle:v1//prod,
+// pulled with the given puller.  Loaders of OCI artifacts, like
+// those of git clones, are restricted to the artifact.
+func NewLoaderWithOciPuller(
+	lr LoadRestrictorFunc, target string,
+	fSys filesys.FileSystem, puller ifc.OciPuller) (ifc.Loader, error) {
+	if isOciRef(target) {
+		return newOciLoader(target, nil, fSys, git.ClonerUsingGitExec, puller)
+	}
+	ldr, err := NewLoader(lr, target, fSys)
+	if err != nil {
+		return nil, err
+	}
+	ldr.(*fileLoader).ociPuller = puller
+	return ldr, nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/ifc"
+	"sigs.k8s.io/kustomize/api/internal/git"
+)
+
+// ociScheme prefixes references to OCI artifacts, which may be
+// followed by '//' and a directory in the artifact, e.g.
+// oci://registry.example.com/config/bundle:v1//overlays/prod
+const ociScheme = "oci://"
+
+func isOciRef(s string) bool {
+	return strings.HasPrefix(s, ociScheme)
+}
+
+// parseOciRef returns the artifact reference and the cleaned
+// directory within the artifact, '.' if none is given.
+func parseOciRef(s string) (ref, dir string) {
+	s = strings.TrimPrefix(s, ociScheme)
+	if i := strings.Index(s, "//"); i >= 0 {
+		return s[:i], path.Clean(s[i+2:])
+	}
+	return s, "."
+}
+
+// escapes returns true if the cleaned, slash separated,
+// relative path leads out of the directory it's relative to.
+func escapes(p string) bool {
+	return p == ".." || strings.HasPrefix(p, "../")
+}
+
+// ociLoader loads the files of a pulled OCI artifact.  Like a
+// loader of a cloned git repo, it never loads files outside of
+// the artifact, and relative bases must be in the artifact too.
+type ociLoader struct {
+	// Loader that spawned this loader.
+	// Used to avoid cycles.
+	referrer *ociLoader
+
+	// The reference the artifact was pulled with.
+	ref string
+
+	// A cleaned, slash separated path to a directory of
+	// the artifact, relative to its root.
+	root string
+
+	// The files of the artifact, by cleaned paths
+	// relative to its root.
+	files map[string][]byte
+
+	fSys   filesys.FileSystem
+	cloner git.Cloner
+	puller ifc.OciPuller
+}
+
+// newOciLoader returns a Loader pinned to the directory of
+// the OCI artifact that the target refers to.
+func newOciLoader(
+	target string, referrer *ociLoader, fSys filesys.FileSystem,
+	cloner git.Cloner, puller ifc.OciPuller) (ifc.Loader, error) {
+	if puller == nil {
+		return nil, fmt.Errorf(
+			"cannot load '%s', as no OCI puller is configured", target)
+	}
+	ref, dir := parseOciRef(target)
+	if ref == "" {
+		return nil, fmt.Errorf("'%s' lacks an OCI artifact reference", target)
+	}
+	if path.IsAbs(dir) || escapes(dir) {
+		return nil, fmt.Errorf(
+			"'%s' must name a directory within the OCI artifact", target)
+	}
+	for r := referrer; r != nil; r = r.referrer {
+		if r.ref == ref {
+			return nil, fmt.Errorf(
+				"cycle detected: OCI artifact '%s' referenced by itself", ref)
+		}
+	}
+	pulled, err := puller.Pull(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "pulling OCI artifact '%s'", ref)
+	}
+	if len(pulled) == 0 {
+		return nil, fmt.Errorf(
+			"OCI artifact '%s' has no layers holding files", ref)
+	}
+	files := make(map[string][]byte, len(pulled))
+	for p, content := range pulled {
+		files[path.Clean(strings.TrimPrefix(p, "/"))] = content
+	}
+	l := &ociLoader{
+		referrer: referrer,
+		ref:      ref,
+		root:     dir,
+		files:    files,
+		fSys:     fSys,
+		cloner:   cloner,
+		puller:   puller,
+	}
+	if !l.isDir(dir) {
+		return nil, fmt.Errorf(
+			"'%s' is not a directory of OCI artifact '%s'", dir, ref)
+	}
+	return l, nil
+}
+
+// Root returns the reference to the loader's directory.
+func (l *ociLoader) Root() string {
+	if l.root == "." {
+		return ociScheme + l.ref
+	}
+	return ociScheme + l.ref + "//" + l.root
+}
+
+// New returns a new Loader, rooted relative to this one's
+// directory in the artifact, or at another OCI artifact or git
+// repository.
+func (l *ociLoader) New(newRoot string) (ifc.Loader, error) {
+	if newRoot == "" {
+		return nil, fmt.Errorf("new root cannot be empty")
+	}
+	if isOciRef(newRoot) {
+		return newOciLoader(newRoot, l, l.fSys, l.cloner, l.puller)
+	}
+	repoSpec, err := git.NewRepoSpecFromUrl(newRoot)
+	if err == nil {
+		ldr, err := newLoaderAtGitClone(repoSpec, l.fSys, nil, l.cloner)
+		if err != nil {
+			return nil, err
+		}
+		ldr.(*fileLoader).ociPuller = l.puller
+		return ldr, nil
+	}
+	if path.IsAbs(newRoot) || filepath.IsAbs(newRoot) {
+		return nil, fmt.Errorf("new root '%s' cannot be absolute", newRoot)
+	}
+	dir := path.Join(l.root, filepath.ToSlash(newRoot))
+	if escapes(dir) {
+		return nil, fmt.Errorf(
+			"security; base '%s' is outside OCI artifact '%s'", newRoot, l.ref)
+	}
+	for r := l; r != nil && r.ref == l.ref; r = r.referrer {
+		if dir == "." || dir == r.root || strings.HasPrefix(r.root, dir+"/") {
+			return nil, fmt.Errorf(
+				"cycle detected: candidate root '%s' contains visited root '%s'",
+				dir, r.root)
+		}
+	}
+	if !l.isDir(dir) {
+		return nil, fmt.Errorf(
+			"'%s' is not a directory of OCI artifact '%s'", dir, l.ref)
+	}
+	return &ociLoader{
+		referrer: l,
+		ref:      l.ref,
+		root:     dir,
+		files:    l.files,
+		fSys:     l.fSys,
+		cloner:   l.cloner,
+		puller:   l.puller,
+	}, nil
+}
+
+// Load returns the content of the file of the artifact at the
+// given path, taken relative to the loader's directory.
+func (l *ociLoader) Load(p string) ([]byte, error) {
+	if path.IsAbs(p) || filepath.IsAbs(p) {
+		return nil, fmt.Errorf(
+			"file '%s' must be relative to OCI artifact '%s'", p, l.ref)
+	}
+	full := path.Join(l.root, filepath.ToSlash(p))
+	if escapes(full) {
+		return nil, fmt.Errorf(
+			"security; file '%s' is outside OCI artifact '%s'", p, l.ref)
+	}
+	content, ok := l.files[full]
+	if !ok {
+		return nil, fmt.Errorf(
+			"file '%s' not found in OCI artifact '%s'", full, l.ref)
+	}
+	return content, nil
+}
+
+// Cleanup does nothing, as the files are held in memory.
+func (l *ociLoader) Cleanup() error {
+	return nil
+}
+
+// isDir returns true if the artifact holds files below dir.
+func (l *ociLoader) isDir(dir string) bool {
+	if dir == "." {
+		return len(l.files) > 0
+	}
+	for p := range l.files {
+		if strings.HasPrefix(p, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package loader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/internal/git"
+)
+
+// stubOciPuller serves artifacts from memory, by reference.
+type stubOciPuller map[string]map[string][]byte
+
+func (p stubOciPuller) Pull(ref string) (map[string][]byte, error) {
+	files, ok := p[ref]
+	if !ok {
+		return nil, fmt.Errorf("manifest unknown")
+	}
+	return files, nil
+}
+
+var bundlePuller = stubOciPuller{
+	"registry.example.com/bundle:v1": {
+		"base/kustomization.yaml":          []byte("resources:\n- deploy.yaml\n"),
+		"base/deploy.yaml":                 []byte("kind: Deployment\n"),
+		"overlays/prod/kustomization.yaml": []byte("resources:\n- ../../base\n"),
+	},
+	"registry.example.com/empty:v1": {},
+}
+
+func TestOciLoader(t *testing.T) {
+	ldr, err := NewLoaderWithOciPuller(RestrictionRootOnly,
+		"oci://registry.example.com/bundle:v1//overlays/prod",
+		filesys.MakeFsInMemory(), bundlePuller)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ldr.Root() != "oci://registry.example.com/bundle:v1//overlays/prod" {
+		t.Fatalf("unexpected root: %s", ldr.Root())
+	}
+	if _, err = ldr.Load("kustomization.yaml"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base, err := ldr.New("../../base")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := base.Load("deploy.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(content) != "kind: Deployment\n" {
+		t.Fatalf("unexpected content: %s", content)
+	}
+}
+
+func TestOciLoaderErrors(t *testing.T) {
+	ldr, err := newOciLoader("oci://registry.example.com/bundle:v1//base",
+		nil, filesys.MakeFsInMemory(), git.ClonerUsingGitExec, bundlePuller)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testCases := map[string]struct {
+		do       func() error
+		expected string
+	}{
+		"pull failure": {
+			do: func() error {
+				_, err := ldr.New("oci://registry.example.com/missing:v1")
+				return err
+			},
+			expected: "pulling OCI artifact 'registry.example.com/missing:v1': manifest unknown",
+		},
+		"no layers": {
+			do: func() error {
+				_, err := ldr.New("oci://registry.example.com/empty:v1")
+				return err
+			},
+			expected: "OCI artifact 'registry.example.com/empty:v1' has no layers holding files",
+		},
+		"missing file": {
+			do: func() error {
+				_, err := ldr.Load("service.yaml")
+				return err
+			},
+			expected: "file 'base/service.yaml' not found in OCI artifact",
+		},
+		"file outside artifact": {
+			do: func() error {
+				_, err := ldr.Load("../../etc/passwd")
+				return err
+			},
+			expected: "security; file '../../etc/passwd' is outside OCI artifact",
+		},
+		"base outside artifact": {
+			do: func() error {
+				_, err := ldr.New("../..")
+				return err
+			},
+			expected: "security; base '../..' is outside OCI artifact",
+		},
+		"base above root": {
+			do: func() error {
+				_, err := ldr.New("..")
+				return err
+			},
+			expected: "cycle detected",
+		},
+		"missing directory": {
+			do: func() error {
+				_, err := ldr.New("../components")
+				return err
+			},
+			expected: "'components' is not a directory of OCI artifact",
+		},
+		"cycle": {
+			do: func() error {
+				_, err := ldr.New("oci://registry.example.com/bundle:v1//overlays/prod")
+				return err
+			},
+			expected: "cycle detected: OCI artifact 'registry.example.com/bundle:v1'",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.do()
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Fatalf("expected error containing %q, got: %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestOciRefWithoutPuller(t *testing.T) {
+	_, err := NewLoaderWithOciPuller(RestrictionRootOnly,
+		"oci://registry.example.com/bundle:v1", filesys.MakeFsInMemory(), nil)
+	if err == nil || !strings.Contains(err.Error(), "no OCI puller is configured") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -6,6 +6,8 @@
 package resmap
 
 import (
+	"time"
+
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/resid"
 	"sigs.k8s.io/kustomize/api/resource"
@@ -20,6 +22,14 @@ type Transformer interface {
 	Transform(m ResMap) error
 }
 
+// A PatchReporter is a Transformer that can report
+// the fields each of its patches changed.
+type PatchReporter interface {
+	// PatchReports returns the reports of the patches applied
+	// by the most recent Transform.
+	PatchReports() []types.PatchReport
+}
+
 // A Generator creates an instance of ResMap.
 type Generator interface {
 	Generate() (ResMap, error)
@@ -44,6 +54,7 @@ type PluginHelpers struct {
 	ldr ifc.Loader
 	v   ifc.Validator
 	rf  *Factory
+	now func() time.Time
 }
 
 func (c *PluginHelpers) Loader() ifc.Loader {
@@ -58,6 +69,23 @@ func (c *PluginHelpers) Validator() ifc.Validator {
 	return c.v
 }
 
+// Now returns the time to use for any timestamp a plugin puts
+// into its output.  It's the current time unless the build
+// fixed it, so that repeated builds give identical output.
+func (c *PluginHelpers) Now() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
+
+// WithNow returns a copy of the helpers whose Now calls f.
+func (c *PluginHelpers) WithNow(f func() time.Time) *PluginHelpers {
+	h := *c
+	h.now = f
+	return &h
+}
+
 type GeneratorPlugin interface {
 	Generator
 	Configurable
@@ -128,6 +156,10 @@ type ResMap interface {
 	// AsYaml returns the yaml form of resources.
 	AsYaml() ([]byte, error)
 
+	// AsYamlWithOptions returns the yaml form of resources,
+	// with the indentation and quoting the options pin.
+	AsYamlWithOptions(opts types.YamlOptions) ([]byte, error)
+
 	// GetByIndex returns a resource at the given index,
 	// nil if out of range.
 	GetByIndex(int) *resource.Resource
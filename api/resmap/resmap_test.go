@@ -3,4 +3,27 @@
 
 package resmap_test
 
+import (
+	"testing"
+	"time"
+
+	. "sigs.k8s.io/kustomize/api/resmap"
+)
+
 // See reswrangler_test.go
+
+func TestPluginHelpersNow(t *testing.T) {
+	h := NewPluginHelpers(nil, nil, nil)
+	before := time.Now()
+	if now := h.Now(); now.Before(before) {
+		t.Fatalf("expected the current time, got %v", now)
+	}
+	fixed := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	pinned := h.WithNow(func() time.Time { return fixed })
+	if !pinned.Now().Equal(fixed) {
+		t.Fatalf("expected %v, got %v", fixed, pinned.Now())
+	}
+	if h.Now().Equal(fixed) {
+		t.Fatalf("WithNow changed the original helpers")
+	}
+}
@@ -270,11 +270,23 @@ func (m *resWrangler) groupedByOriginalNamespace() map[string][]*resource.Resour
 
 // AsYaml implements ResMap.
 func (m *resWrangler) AsYaml() ([]byte, error) {
+	return m.asYaml((*resource.Resource).AsYAML)
+}
+
+// AsYamlWithOptions implements ResMap.
+func (m *resWrangler) AsYamlWithOptions(opts types.YamlOptions) ([]byte, error) {
+	return m.asYaml(func(r *resource.Resource) ([]byte, error) {
+		return r.AsYAMLWithOptions(opts)
+	})
+}
+
+func (m *resWrangler) asYaml(
+	toYaml func(*resource.Resource) ([]byte, error)) ([]byte, error) {
 	firstObj := true
 	var b []byte
 	buf := bytes.NewBuffer(b)
 	for _, res := range m.Resources() {
-		out, err := res.AsYAML()
+		out, err := toYaml(res)
 		if err != nil {
 			m, _ := res.Map()
 			return nil, errors.Wrapf(err, "%#v", m)
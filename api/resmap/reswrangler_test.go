@@ -1349,3 +1349,110 @@ spec:
         name: nginx
 `, imagename)
 }
+
+func TestAsYamlWithOptions(t *testing.T) {
+	inputs := []string{`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: 'settings'
+  labels: {app: web}
+data:
+  mode: fast
+  enabled: "true"
+  motd: |
+    hello
+    world
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  ports:
+  - port: 80
+    name: "http"
+`, `
+apiVersion: "v1"
+kind: "ConfigMap"
+metadata:
+    name: settings
+    labels:
+        "app": 'web'
+data:
+    mode: 'fast'
+    enabled: 'true'
+    motd: "hello\nworld\n"
+---
+apiVersion: v1
+kind: Service
+metadata:
+    name: "web"
+spec:
+    ports: [{port: 80, name: http}]
+`}
+	testCases := map[string]struct {
+		opts     types.YamlOptions
+		expected string
+	}{
+		"minimal": {
+			opts: types.YamlOptions{Indent: 4},
+			expected: `apiVersion: v1
+data:
+    enabled: "true"
+    mode: fast
+    motd: |
+        hello
+        world
+kind: ConfigMap
+metadata:
+    labels:
+        app: web
+    name: settings
+---
+apiVersion: v1
+kind: Service
+metadata:
+    name: web
+spec:
+    ports:
+      - name: http
+        port: 80
+`,
+		},
+		"double": {
+			opts: types.YamlOptions{QuoteStyle: types.QuoteDouble},
+			expected: `apiVersion: "v1"
+data:
+  enabled: "true"
+  mode: "fast"
+  motd: "hello\nworld\n"
+kind: "ConfigMap"
+metadata:
+  labels:
+    app: "web"
+  name: "settings"
+---
+apiVersion: "v1"
+kind: "Service"
+metadata:
+  name: "web"
+spec:
+  ports:
+  - name: "http"
+    port: 80
+`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, input := range inputs {
+				m, err := rmF.NewResMapFromBytes([]byte(input))
+				assert.NoError(t, err)
+				out, err := m.AsYamlWithOptions(tc.opts)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, string(out))
+			}
+		})
+	}
+}
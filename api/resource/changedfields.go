@@ -0,0 +1,65 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// ChangedFields returns the sorted paths of the fields that
+// differ between the two maps of a resource, e.g. spec.replicas.
+// A field added or removed is reported, but not the fields in
+// it, as is a list whose length changed.  Lists of the same
+// length are compared by index, e.g. spec.ports[0].port.
+func ChangedFields(before, after map[string]interface{}) []string {
+	result := []string{}
+	changedFields("", before, after, &result)
+	sort.Strings(result)
+	return result
+}
+
+func changedFields(path string, before, after interface{}, result *[]string) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for k, bv := range b {
+			av, found := a[k]
+			if !found {
+				*result = append(*result, joinField(path, k))
+				continue
+			}
+			changedFields(joinField(path, k), bv, av, result)
+		}
+		for k := range a {
+			if _, found := b[k]; !found {
+				*result = append(*result, joinField(path, k))
+			}
+		}
+		return
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok || len(a) != len(b) {
+			break
+		}
+		for i := range b {
+			changedFields(path+"["+strconv.Itoa(i)+"]", b[i], a[i], result)
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*result = append(*result, path)
+	}
+}
+
+func joinField(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
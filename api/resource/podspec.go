@@ -0,0 +1,29 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+// PodTemplatePaths returns the paths, relative to a resource of
+// the given kind, at which it may hold a pod template, e.g. in a
+// Deployment or a CronJob.  A Pod is its own template, so for a
+// Pod the only path is the empty one.
+func PodTemplatePaths(kind string) [][]string {
+	if kind == "Pod" {
+		return [][]string{{}}
+	}
+	return [][]string{
+		{"spec", "template"},
+		{"spec", "jobTemplate", "spec", "template"},
+	}
+}
+
+// PodSpecPaths returns the paths, relative to a resource of the
+// given kind, at which it may hold a pod spec, i.e. the spec of
+// each of its PodTemplatePaths.
+func PodSpecPaths(kind string) [][]string {
+	var result [][]string
+	for _, path := range PodTemplatePaths(kind) {
+		result = append(result, append(path, "spec"))
+	}
+	return result
+}
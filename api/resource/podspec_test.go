@@ -0,0 +1,27 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	. "sigs.k8s.io/kustomize/api/resource"
+)
+
+func TestPodSpecPaths(t *testing.T) {
+	assert.Equal(t, [][]string{{"spec"}}, PodSpecPaths("Pod"))
+	assert.Equal(t, [][]string{
+		{"spec", "template", "spec"},
+		{"spec", "jobTemplate", "spec", "template", "spec"},
+	}, PodSpecPaths("Deployment"))
+}
+
+func TestPodTemplatePaths(t *testing.T) {
+	assert.Equal(t, [][]string{{}}, PodTemplatePaths("Pod"))
+	assert.Equal(t, [][]string{
+		{"spec", "template"},
+		{"spec", "jobTemplate", "spec", "template"},
+	}, PodTemplatePaths("CronJob"))
+}
@@ -4,6 +4,7 @@
 package resource
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"log"
@@ -30,6 +31,8 @@ type Resource struct {
 	options     *types.GenArgs
 	refBy       []resid.ResId
 	refVarNames []string
+	origin      string
+	provenance  []types.ProvenanceStep
 }
 
 const (
@@ -199,6 +202,8 @@ func (r *Resource) copyOtherFields(other *Resource) {
 	r.options = other.options
 	r.refBy = other.copyRefBy()
 	r.refVarNames = copyStringSlice(other.refVarNames)
+	r.origin = other.origin
+	r.provenance = append([]types.ProvenanceStep(nil), other.provenance...)
 }
 
 func (r *Resource) MergeDataMapFrom(o *Resource) {
@@ -376,6 +381,63 @@ func (r *Resource) AsYAML() ([]byte, error) {
 	return yaml.JSONToYAML(json)
 }
 
+// AsYAMLWithOptions returns the resource in YAML form, with
+// the indentation and quoting the options pin, whatever the
+// formatting of the input.
+func (r *Resource) AsYAMLWithOptions(opts types.YamlOptions) ([]byte, error) {
+	json, err := r.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	node, err := kyaml.Parse(string(json))
+	if err != nil {
+		return nil, err
+	}
+	restyle(node.YNode(), opts.QuoteStyle)
+	indent := opts.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+	var buf bytes.Buffer
+	e := kyaml.NewEncoder(&buf)
+	e.SetIndent(indent)
+	if err = e.Encode(node.YNode()); err != nil {
+		return nil, err
+	}
+	if err = e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// restyle drops the style the node and its descendants were
+// parsed with, i.e. JSON's flow collections and quotes, and gives
+// string values the quote style.
+func restyle(n *kyaml.Node, style types.QuoteStyle) {
+	n.Style = 0
+	switch n.Kind {
+	case kyaml.MappingNode:
+		for i := 0; i < len(n.Content); i += 2 {
+			restyle(n.Content[i], types.QuoteMinimal)
+			restyle(n.Content[i+1], style)
+		}
+	case kyaml.SequenceNode:
+		for _, c := range n.Content {
+			restyle(c, style)
+		}
+	case kyaml.ScalarNode:
+		if n.Tag != kyaml.NodeTagString {
+			return
+		}
+		switch style {
+		case types.QuoteDouble:
+			n.Style = kyaml.DoubleQuotedStyle
+		case types.QuoteSingle:
+			n.Style = kyaml.SingleQuotedStyle
+		}
+	}
+}
+
 // MustYaml returns YAML or panics.
 func (r *Resource) MustYaml() string {
 	yml, err := r.AsYAML()
@@ -401,6 +463,35 @@ func (r *Resource) NeedHashSuffix() bool {
 	return r.options != nil && r.options.ShouldAddHashSuffixToName()
 }
 
+// GetOrigin returns the path of the base the resource came from,
+// relative to the kustomization being built, or the empty string
+// if it belongs to that kustomization.
+func (r *Resource) GetOrigin() string {
+	return r.origin
+}
+
+// SetOrigin sets the path of the base the resource came from.
+func (r *Resource) SetOrigin(origin string) {
+	r.origin = origin
+}
+
+// GetProvenance returns the steps in the making of the
+// resource noted so far, in the order they happened.
+func (r *Resource) GetProvenance() []types.ProvenanceStep {
+	return r.provenance
+}
+
+// AddProvenance notes another step in the making of the resource.
+func (r *Resource) AddProvenance(step types.ProvenanceStep) {
+	r.provenance = append(r.provenance, step)
+}
+
+// GeneratorLabels returns the labels that the generator of the
+// resource added, and whether they should win over commonLabels.
+func (r *Resource) GeneratorLabels() (map[string]string, bool) {
+	return r.options.GeneratorLabels()
+}
+
 // GetNamespace returns the namespace the resource thinks it's in.
 func (r *Resource) GetNamespace() string {
 	namespace, _ := r.GetString("metadata.namespace")
@@ -0,0 +1,66 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import "sigs.k8s.io/kustomize/api/resid"
+
+// BuildMetadata holds information gathered about a build,
+// as opposed to the resources the build emits.
+// Fields are only filled in when the build was asked to
+// collect the corresponding information.
+type BuildMetadata struct {
+	// Transformations lists, for every resource in the build
+	// output, in output order, the transformers that changed it.
+	Transformations []Transformation `json:"transformations,omitempty" yaml:"transformations,omitempty"`
+
+	// Dependencies lists, for the resources in the build output,
+	// each field of one resource that refers to another, e.g. a
+	// Deployment volume naming a ConfigMap.  Together they form
+	// the dependency graph of the output.
+	Dependencies []Dependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+
+	// Deleted lists the ids of the resources that a previous
+	// build, given to compare against, emitted but this build
+	// doesn't, i.e. those an incremental apply should delete.
+	Deleted []resid.ResId `json:"deleted,omitempty" yaml:"deleted,omitempty"`
+
+	// Patches lists, for every target of every patch applied,
+	// the fields the patch changed.
+	Patches []PatchReport `json:"patches,omitempty" yaml:"patches,omitempty"`
+}
+
+// Transformation holds the names of the transformers that
+// changed a resource, in the order that they ran.  A resource
+// no transformer changed has none.
+type Transformation struct {
+	Resource     resid.ResId `json:"resource" yaml:"resource"`
+	Transformers []string    `json:"transformers" yaml:"transformers"`
+}
+
+// PatchReport holds the fields of its target a patch changed.
+type PatchReport struct {
+	// Patch is where the patch came from, e.g. its file.
+	Patch string `json:"patch" yaml:"patch"`
+
+	// Target is the id of the target when the patch was applied.
+	Target resid.ResId `json:"target" yaml:"target"`
+
+	// Fields are the sorted paths of the fields the patch added,
+	// removed or changed, e.g. spec.replicas or
+	// spec.template.spec.containers[0].image.  A patch that left
+	// its target unchanged has none.
+	Fields []string `json:"fields" yaml:"fields"`
+}
+
+// Dependency is an edge of the dependency graph: the resource
+// From refers to the resource To in the given field.
+type Dependency struct {
+	From resid.ResId `json:"from" yaml:"from"`
+	To   resid.ResId `json:"to" yaml:"to"`
+
+	// Field is the path of the referring field in From, e.g.
+	// spec/template/spec/volumes/configMap/name, or
+	// metadata/ownerReferences for an owner reference.
+	Field string `json:"field" yaml:"field"`
+}
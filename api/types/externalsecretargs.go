@@ -0,0 +1,45 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// ExternalSecretArgs contains the metadata of how to generate
+// an ExternalSecret, i.e. the custom resource from which the
+// External Secrets Operator makes a Secret.
+type ExternalSecretArgs struct {
+	// Name of the ExternalSecret.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Namespace of the ExternalSecret, optional.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// SecretStoreRef names the store holding the secret data.
+	SecretStoreRef SecretStoreRef `json:"secretStoreRef,omitempty" yaml:"secretStoreRef,omitempty"`
+
+	// Target is the name of the Secret the operator makes.
+	// It defaults to the name of the ExternalSecret.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// RefreshInterval, e.g. "1h", is how often the operator
+	// reads the store again; optional.
+	RefreshInterval string `json:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty"`
+
+	// Data maps the keys of the Secret to keys in the store.
+	Data []ExternalSecretData `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// SecretStoreRef refers to a SecretStore or ClusterSecretStore.
+type SecretStoreRef struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Kind is SecretStore, the default, or ClusterSecretStore.
+	Kind string `json:"kind,omitempty" yaml:"kind,omitempty"`
+}
+
+// ExternalSecretData maps a key of the generated Secret to
+// a key, and optionally a property of its value, in the store.
+type ExternalSecretData struct {
+	SecretKey string `json:"secretKey,omitempty" yaml:"secretKey,omitempty"`
+	RemoteKey string `json:"remoteKey,omitempty" yaml:"remoteKey,omitempty"`
+	Property  string `json:"property,omitempty" yaml:"property,omitempty"`
+}
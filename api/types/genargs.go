@@ -44,3 +44,12 @@ func (g *GenArgs) Behavior() GenerationBehavior {
 	}
 	return NewGenerationBehavior(g.args.Behavior)
 }
+
+// GeneratorLabels returns the labels of the generator options,
+// and whether the options ask to inherit commonLabels.
+func (g *GenArgs) GeneratorLabels() (map[string]string, bool) {
+	if g == nil || g.args == nil || g.args.Options == nil {
+		return nil, false
+	}
+	return g.args.Options.Labels, g.args.Options.InheritCommonLabels
+}
@@ -22,6 +22,10 @@ type GeneratorArgs struct {
 	// KvPairSources for the generator.
 	KvPairSources `json:",inline,omitempty" yaml:",inline,omitempty"`
 
+	// Optional, if true, skips the generator, with a warning,
+	// when a file it reads from is missing, rather than failing.
+	Optional bool `json:"optional,omitempty" yaml:"optional,omitempty"`
+
 	// Local overrides to global generatorOptions field.
 	Options *GeneratorOptions `json:"options,omitempty" yaml:"options,omitempty"`
 }
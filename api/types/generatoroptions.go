@@ -11,10 +11,25 @@ type GeneratorOptions struct {
 	// Annotations to add to all generated resources.
 	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 
+	// Finalizers to add to all generated resources.  They don't
+	// contribute to the name suffix hash.
+	Finalizers []string `json:"finalizers,omitempty" yaml:"finalizers,omitempty"`
+
+	// FieldManager, if not empty, is recorded in an annotation
+	// of all generated resources, as a hint of the field manager
+	// to use when applying them server side.  It doesn't
+	// contribute to the name suffix hash.
+	FieldManager string `json:"fieldManager,omitempty" yaml:"fieldManager,omitempty"`
+
 	// DisableNameSuffixHash if true disables the default behavior of adding a
 	// suffix to the names of generated resources that is a hash of the
 	// resource contents.
 	DisableNameSuffixHash bool `json:"disableNameSuffixHash,omitempty" yaml:"disableNameSuffixHash,omitempty"`
+
+	// InheritCommonLabels if true gives generated resources the
+	// commonLabels of the kustomization too, with the labels above
+	// winning over commonLabels of the same key.
+	InheritCommonLabels bool `json:"inheritCommonLabels,omitempty" yaml:"inheritCommonLabels,omitempty"`
 }
 
 // MergeGlobalOptionsIntoLocal merges two instances of GeneratorOptions.
@@ -39,9 +54,16 @@ func MergeGlobalOptionsIntoLocal(
 	}
 	overrideMap(&localOpts.Labels, globalOpts.Labels)
 	overrideMap(&localOpts.Annotations, globalOpts.Annotations)
+	localOpts.Finalizers = appendMissing(localOpts.Finalizers, globalOpts.Finalizers)
+	if localOpts.FieldManager == "" {
+		localOpts.FieldManager = globalOpts.FieldManager
+	}
 	if globalOpts.DisableNameSuffixHash {
 		localOpts.DisableNameSuffixHash = true
 	}
+	if globalOpts.InheritCommonLabels {
+		localOpts.InheritCommonLabels = true
+	}
 	return localOpts
 }
 
@@ -60,6 +82,24 @@ func overrideMap(localMap *map[string]string, globalMap map[string]string) {
 	}
 }
 
+// appendMissing returns local with the values of global
+// that it lacks appended.
+func appendMissing(local, global []string) []string {
+	for _, v := range global {
+		found := false
+		for _, l := range local {
+			if l == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			local = append(local, v)
+		}
+	}
+	return local
+}
+
 // CopyMap copies a map.
 func CopyMap(in map[string]string) map[string]string {
 	out := make(map[string]string)
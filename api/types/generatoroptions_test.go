@@ -78,6 +78,31 @@ func TestMergeGlobalOptionsIntoLocal(t *testing.T) {
 				DisableNameSuffixHash: false,
 			},
 		},
+		{
+			name: "finalizers are combined",
+			local: &GeneratorOptions{
+				Finalizers: []string{"example.com/a", "example.com/b"},
+			},
+			global: &GeneratorOptions{
+				Finalizers: []string{"example.com/b", "example.com/c"},
+			},
+			expected: &GeneratorOptions{
+				Finalizers: []string{
+					"example.com/a", "example.com/b", "example.com/c"},
+			},
+		},
+		{
+			name: "local field manager wins",
+			local: &GeneratorOptions{
+				FieldManager: "deployer",
+			},
+			global: &GeneratorOptions{
+				FieldManager: "platform",
+			},
+			expected: &GeneratorOptions{
+				FieldManager: "deployer",
+			},
+		},
 		{
 			name: "global disable trumps local",
 			local: &GeneratorOptions{
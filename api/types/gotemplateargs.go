@@ -0,0 +1,19 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// GoTemplateArgs contains the metadata of how to generate
+// objects from a Go text/template.
+type GoTemplateArgs struct {
+	// Template is the path to a file holding a Go text/template
+	// that renders to one or more resources.  Besides the usual
+	// functions, it may call now for the time of the build, e.g.
+	// "{{ now.UTC.Format \"2006-01-02\" }}"; see BuildTime.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+
+	// Values are what the template is executed against, e.g.
+	// "{{ .replicas }}" renders the value of replicas.  Referring
+	// to a missing value is an error.
+	Values map[string]interface{} `json:"values,omitempty" yaml:"values,omitempty"`
+}
@@ -15,7 +15,24 @@ type Image struct {
 	// NewTag is the value used to replace the original tag.
 	NewTag string `json:"newTag,omitempty" yaml:"newTag,omitempty"`
 
+	// NewTagEnv names an environment variable holding the new tag,
+	// e.g. one set by CI.  It's an alternative to NewTag that is
+	// only honored when the build enables tags from the environment.
+	NewTagEnv string `json:"newTagEnv,omitempty" yaml:"newTagEnv,omitempty"`
+
 	// Digest is the value used to replace the original image tag.
 	// If digest is present NewTag value is ignored.
+	// If NewDigest is also present, Digest instead restricts the
+	// entry to images pinned to exactly this digest.
 	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+
+	// NewDigest is the value used to replace the original digest,
+	// so that a pinned image can be rotated without knowing its tag.
+	NewDigest string `json:"newDigest,omitempty" yaml:"newDigest,omitempty"`
+
+	// Profile, if not empty, groups this entry with all other
+	// entries of the same profile name; the entry only applies
+	// when that profile is the one selected for the build.
+	// Entries without a profile apply to every build.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
 }
@@ -0,0 +1,19 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// JsonArrayArgs contains the metadata of how to generate
+// objects from the elements of a JSON array.
+type JsonArrayArgs struct {
+	// Source is the path to a file holding a JSON array
+	// of objects, one per set of objects to generate.
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+
+	// Template is the path to a file holding one or more
+	// resources.  Every string value in it may refer to
+	// fields of an array element, e.g. "{{ .name }}".
+	// If a value consists of a reference alone, it takes
+	// on the type of the referenced field.
+	Template string `json:"template,omitempty" yaml:"template,omitempty"`
+}
@@ -43,9 +43,18 @@ type Kustomization struct {
 	// Namespace to add to all objects.
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 
+	// ProfileNamespaces maps build profiles, e.g. "prod", to the
+	// namespace to add to all objects, in place of Namespace,
+	// when that profile is the one selected for the build.
+	ProfileNamespaces map[string]string `json:"profileNamespaces,omitempty" yaml:"profileNamespaces,omitempty"`
+
 	// CommonLabels to add to all objects and selectors.
 	CommonLabels map[string]string `json:"commonLabels,omitempty" yaml:"commonLabels,omitempty"`
 
+	// RecommendedLabels, if set, are the values of the recommended
+	// app.kubernetes.io labels, added like CommonLabels.
+	RecommendedLabels *RecommendedLabels `json:"recommendedLabels,omitempty" yaml:"recommendedLabels,omitempty"`
+
 	// CommonAnnotations to add to all objects.
 	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty" yaml:"commonAnnotations,omitempty"`
 
@@ -127,9 +136,26 @@ type Kustomization struct {
 
 	// HelmChartInflationGenerator is a list of helm chart configurations.
 	// The resulting resource is a normal operand rendered from
-	// a remote chart by `helm template`
+	// a remote chart by `helm template`, or by the helm inflater
+	// the build was given, if any.
 	HelmChartInflationGenerator []HelmChartArgs `json:"helmChartInflationGenerator,omitempty" yaml:"helmChartInflationGenerator,omitempty"`
 
+	// JsonArrayGenerator is a list of JSON array sources, each
+	// paired with a template rendered once per array element.
+	// The resulting resources are normal operands.
+	JsonArrayGenerator []JsonArrayArgs `json:"jsonArrayGenerator,omitempty" yaml:"jsonArrayGenerator,omitempty"`
+
+	// ExternalSecretGenerator is a list of ExternalSecrets to
+	// generate from a compact description (one per list item).
+	// Unlike the secretGenerator, it makes no Secret; the External
+	// Secrets Operator does so in the cluster.
+	ExternalSecretGenerator []ExternalSecretArgs `json:"externalSecretGenerator,omitempty" yaml:"externalSecretGenerator,omitempty"`
+
+	// GoTemplateGenerator is a list of Go templates, each executed
+	// against its values to render resources.  The resulting
+	// resources are normal operands.
+	GoTemplateGenerator []GoTemplateArgs `json:"goTemplateGenerator,omitempty" yaml:"goTemplateGenerator,omitempty"`
+
 	// GeneratorOptions modify behavior of all ConfigMap and Secret generators.
 	GeneratorOptions *GeneratorOptions `json:"generatorOptions,omitempty" yaml:"generatorOptions,omitempty"`
 
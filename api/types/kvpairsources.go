@@ -29,6 +29,24 @@ type KvPairSources struct {
 	// (wikipedia.org/wiki/INI_file)
 	EnvSources []string `json:"envs,omitempty" yaml:"envs,omitempty"`
 
+	// ValuesFiles is a list of file paths.
+	// The contents of each file should be a flat
+	// YAML map of string keys to string values.
+	// A key that is also given in LiteralSources
+	// takes the literal's value.
+	ValuesFiles []string `json:"valuesFiles,omitempty" yaml:"valuesFiles,omitempty"`
+
+	// When true, a key given both in ValuesFiles and
+	// LiteralSources is an error, rather than the
+	// literal overriding the file.
+	StrictValuesFiles bool `json:"strictValuesFiles,omitempty" yaml:"strictValuesFiles,omitempty"`
+
+	// Transform is a list of transformations applied,
+	// in order, to every value before it's stored, e.g.
+	// "trimSpace" to drop leading and trailing white
+	// space, or "jsonPretty" to indent JSON values.
+	Transform []string `json:"transform,omitempty" yaml:"transform,omitempty"`
+
 	// Older, singular form of EnvSources.
 	// On edits (e.g. `kustomize fix`) this is merged into the plural form
 	// for consistency with LiteralSources and FileSources.
@@ -0,0 +1,24 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// ProvenanceStep is a step in the making of a resource: a
+// kustomization including the base the resource came from, or
+// a transformer changing the resource.  The steps of a resource
+// are listed, in the order they happened, in its provenance
+// annotation.
+type ProvenanceStep struct {
+	// Base is the path of the base the resource came from, as
+	// given by the kustomization that includes the base.
+	Base string `json:"base,omitempty" yaml:"base,omitempty"`
+
+	// Component is the path of the component, as given by the
+	// kustomization that includes it, that the transformer
+	// belongs to, if it belongs to one.
+	Component string `json:"component,omitempty" yaml:"component,omitempty"`
+
+	// Transformer is the name of the transformer that changed
+	// the resource, e.g. PatchTransformer.
+	Transformer string `json:"transformer,omitempty" yaml:"transformer,omitempty"`
+}
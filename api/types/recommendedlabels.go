@@ -0,0 +1,47 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// RecommendedLabels holds the values of the labels Kubernetes
+// recommends for applications, see
+// https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/
+// Empty values are left out.
+type RecommendedLabels struct {
+	// Name of the application, e.g. "mysql".
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// Instance identifies this instance of the application,
+	// e.g. "mysql-abcxzy".
+	Instance string `json:"instance,omitempty" yaml:"instance,omitempty"`
+
+	// Version of the application, e.g. "5.7.21".
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Component within the architecture, e.g. "database".
+	Component string `json:"component,omitempty" yaml:"component,omitempty"`
+
+	// PartOf names the higher level application this one
+	// is part of, e.g. "wordpress".
+	PartOf string `json:"partOf,omitempty" yaml:"partOf,omitempty"`
+}
+
+const recommendedLabelPrefix = "app.kubernetes.io/"
+
+// Labels returns the recommended labels, keyed by their
+// full app.kubernetes.io names.
+func (rl RecommendedLabels) Labels() map[string]string {
+	result := make(map[string]string)
+	for k, v := range map[string]string{
+		"name":      rl.Name,
+		"instance":  rl.Instance,
+		"version":   rl.Version,
+		"component": rl.Component,
+		"part-of":   rl.PartOf,
+	} {
+		if v != "" {
+			result[recommendedLabelPrefix+k] = v
+		}
+	}
+	return result
+}
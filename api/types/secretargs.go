@@ -16,4 +16,13 @@ type SecretArgs struct {
 	// If type is "kubernetes.io/tls", then "literals" or "files" must have exactly two
 	// keys: "tls.key" and "tls.crt"
 	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// Subdirectories, if not empty, is a directory holding one
+	// directory per secret to generate, e.g. one per domain of a
+	// set of TLS certificates.  Each secret is named after its
+	// directory and holds the files of that directory as keys,
+	// as well as the keys of the sources above.  Name must be
+	// left empty.  A directory lacking a key its Type requires,
+	// e.g. tls.key for "kubernetes.io/tls", is an error.
+	Subdirectories string `json:"subdirectories,omitempty" yaml:"subdirectories,omitempty"`
 }
@@ -0,0 +1,29 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+// QuoteStyle is how string values are quoted in YAML output.
+type QuoteStyle string
+
+const (
+	// QuoteMinimal quotes only the strings that would
+	// otherwise read as another type, e.g. "true" or "80".
+	QuoteMinimal QuoteStyle = ""
+	// QuoteDouble double quotes every string value.
+	QuoteDouble QuoteStyle = "double"
+	// QuoteSingle single quotes every string value.
+	QuoteSingle QuoteStyle = "single"
+)
+
+// YamlOptions pin the format of YAML output, so that it doesn't
+// depend on how the input was formatted or on the tool version.
+type YamlOptions struct {
+	// Indent is the number of spaces per level of nesting.
+	// Zero means two.
+	Indent int
+
+	// QuoteStyle of string values.  Map keys are only
+	// quoted when they must be.
+	QuoteStyle QuoteStyle
+}
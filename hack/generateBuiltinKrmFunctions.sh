@@ -14,7 +14,24 @@ builtinPlugins=(AnnotationsTransformer \
 	ReplicaCountTransformer \
 	SecretGenerator \
 	ValueAddTransformer \
-	HelmChartInflationGenerator)
+	HelmChartInflationGenerator \
+	MetadataTemplateTransformer \
+	ServiceMonitorTransformer \
+	JsonArrayGenerator \
+	SecurityContextTransformer \
+	ImageNormalizationTransformer \
+	DefaultDenyPolicyTransformer \
+	RecommendedLabelsTransformer \
+	PriorityClassTransformer \
+	DefaultResourceQuotaTransformer \
+	NamespaceRelocationTransformer \
+	DefaultServiceAccountTransformer \
+	ExternalSecretGenerator \
+	GoTemplateGenerator \
+	NodePlacementTransformer \
+	ContainerProbeTransformer \
+	ConfigMapRolloutTransformer \
+	ContainerResourcesTransformer)
 
 builtinPluginDir=../plugin/builtin
 
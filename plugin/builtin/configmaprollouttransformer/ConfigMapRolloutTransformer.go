@@ -0,0 +1,210 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/filters/fsslice"
+	"sigs.k8s.io/kustomize/api/konfig/builtinpluginconsts"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Roll out the consumers of a key of a ConfigMap when its value
+// changes.  The consumers are the workloads that refer to the
+// ConfigMap at the fields of the default nameReference config.
+// If Annotation is set, their pod templates get the annotation,
+// holding a hash of the value, so that changing the value
+// changes the template.  If EnvName is set, the env vars of
+// that name of their containers get the value, unless they're
+// set from another source.
+type plugin struct {
+	Name       string `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Key        string `json:"key,omitempty" yaml:"key,omitempty"`
+	Annotation string `json:"annotation,omitempty" yaml:"annotation,omitempty"`
+	EnvName    string `json:"envName,omitempty" yaml:"envName,omitempty"`
+
+	refs types.FsSlice
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Name = ""
+	p.Namespace = ""
+	p.Key = ""
+	p.Annotation = ""
+	p.EnvName = ""
+	err = k8syaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.Name == "" || p.Key == "" {
+		return fmt.Errorf("must specify the name and key of a ConfigMap")
+	}
+	if p.Annotation == "" && p.EnvName == "" {
+		return fmt.Errorf("must specify an annotation or envName")
+	}
+	var config struct {
+		NameReference []struct {
+			Kind       string        `json:"kind,omitempty" yaml:"kind,omitempty"`
+			FieldSpecs types.FsSlice `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+		} `json:"nameReference,omitempty" yaml:"nameReference,omitempty"`
+	}
+	err = k8syaml.Unmarshal([]byte(
+		builtinpluginconsts.GetDefaultFieldSpecsAsMap()["namereference"]), &config)
+	if err != nil {
+		return err
+	}
+	p.refs = nil
+	for _, r := range config.NameReference {
+		if r.Kind == "ConfigMap" {
+			p.refs = append(p.refs, r.FieldSpecs...)
+		}
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	cm, err := p.findConfigMap(m)
+	if err != nil {
+		return err
+	}
+	value, ok := cm.GetDataMap()[p.Key]
+	if !ok {
+		value, ok = cm.GetBinaryDataMap()[p.Key]
+	}
+	if !ok {
+		return fmt.Errorf("%s has no key '%s'", cm.CurId(), p.Key)
+	}
+	// Until back references are fixed, consumers may still
+	// refer to the ConfigMap by its original name.
+	names := map[string]bool{cm.GetName(): true, cm.OrgId().Name: true}
+	for _, r := range m.Resources() {
+		if r == cm || r.CurId().EffectiveNamespace() != cm.CurId().EffectiveNamespace() {
+			continue
+		}
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				consumes := false
+				_, err := node.Pipe(fsslice.Filter{
+					FsSlice: p.refs,
+					SetValue: func(n *yaml.RNode) error {
+						if names[yaml.GetValue(n)] {
+							consumes = true
+						}
+						return nil
+					},
+				})
+				if err != nil || !consumes {
+					return node, err
+				}
+				return node, p.rollOut(node, r.GetKind(), value)
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findConfigMap returns the ConfigMap with the configured
+// name, current or original, and namespace.
+func (p *plugin) findConfigMap(m resmap.ResMap) (*resource.Resource, error) {
+	var found []*resource.Resource
+	for _, r := range m.Resources() {
+		if r.GetKind() != "ConfigMap" ||
+			(r.GetName() != p.Name && r.OrgId().Name != p.Name) {
+			continue
+		}
+		if p.Namespace != "" && r.CurId().EffectiveNamespace() != p.Namespace {
+			continue
+		}
+		found = append(found, r)
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("found no ConfigMap named '%s'", p.Name)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf(
+			"found %d ConfigMaps named '%s'; specify a namespace",
+			len(found), p.Name)
+	}
+}
+
+// rollOut applies the side effects to the pod templates, or
+// the pod itself, of a consumer.
+func (p *plugin) rollOut(node *yaml.RNode, kind, value string) error {
+	var templates []*yaml.RNode
+	for _, path := range resource.PodTemplatePaths(kind) {
+		tmpl, err := node.Pipe(yaml.Lookup(path...))
+		if err != nil {
+			return err
+		}
+		if tmpl != nil && tmpl.YNode().Kind == yaml.MappingNode {
+			templates = append(templates, tmpl)
+		}
+	}
+	for _, tmpl := range templates {
+		if p.Annotation != "" {
+			err := tmpl.PipeE(
+				yaml.LookupCreate(yaml.MappingNode, "metadata", "annotations"),
+				yaml.SetField(p.Annotation, yaml.NewStringRNode(
+					fmt.Sprintf("%x", sha256.Sum256([]byte(value))))))
+			if err != nil {
+				return err
+			}
+		}
+		if p.EnvName != "" {
+			if err := p.setEnv(tmpl, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setEnv sets the env vars named EnvName of the containers of
+// the pod template to the value, unless they have a valueFrom.
+func (p *plugin) setEnv(tmpl *yaml.RNode, value string) error {
+	for _, field := range []string{"containers", "initContainers"} {
+		containers, err := tmpl.Pipe(yaml.Lookup("spec", field))
+		if err != nil {
+			return err
+		}
+		if containers == nil || containers.YNode().Kind != yaml.SequenceNode {
+			continue
+		}
+		err = containers.VisitElements(func(c *yaml.RNode) error {
+			env, err := c.Pipe(yaml.Lookup("env"))
+			if err != nil || env == nil || env.YNode().Kind != yaml.SequenceNode {
+				return err
+			}
+			return env.VisitElements(func(e *yaml.RNode) error {
+				name, err := e.Pipe(yaml.Lookup("name"))
+				if err != nil || yaml.GetValue(name) != p.EnvName ||
+					e.Field("valueFrom") != nil {
+					return err
+				}
+				return e.PipeE(yaml.SetField("value", yaml.NewStringRNode(value)))
+			})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
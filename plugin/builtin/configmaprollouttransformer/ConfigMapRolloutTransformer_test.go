@@ -0,0 +1,173 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const rolloutInput = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: fast
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+        env:
+        - name: MODE
+          value: slow
+      volumes:
+      - name: settings
+        configMap:
+          name: settings
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  jobTemplate:
+    spec:
+      template:
+        metadata:
+          annotations:
+            team: reports
+        spec:
+          containers:
+          - name: report
+            image: report
+            envFrom:
+            - configMapRef:
+                name: settings
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: other
+spec:
+  template:
+    spec:
+      containers:
+      - name: other
+        image: other
+        env:
+        - name: MODE
+          value: slow
+`
+
+func TestConfigMapRolloutTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ConfigMapRolloutTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ConfigMapRolloutTransformer
+metadata:
+  name: notImportantHere
+name: settings
+key: mode
+annotation: example.com/settings-mode
+envName: MODE
+`, rolloutInput, `
+apiVersion: v1
+data:
+  mode: fast
+kind: ConfigMap
+metadata:
+  name: settings
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      annotations:
+        example.com/settings-mode: 115dc3606fbf8691fb69f2aefec86f2ecd302362a0502b3a9648bf2c4dc8290f
+    spec:
+      containers:
+      - env:
+        - name: MODE
+          value: fast
+        image: web
+        name: web
+      volumes:
+      - configMap:
+          name: settings
+        name: settings
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  jobTemplate:
+    spec:
+      template:
+        metadata:
+          annotations:
+            example.com/settings-mode: 115dc3606fbf8691fb69f2aefec86f2ecd302362a0502b3a9648bf2c4dc8290f
+            team: reports
+        spec:
+          containers:
+          - envFrom:
+            - configMapRef:
+                name: settings
+            image: report
+            name: report
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: other
+spec:
+  template:
+    spec:
+      containers:
+      - env:
+        - name: MODE
+          value: slow
+        image: other
+        name: other
+`)
+}
+
+func TestConfigMapRolloutTransformerMissingKey(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ConfigMapRolloutTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: ConfigMapRolloutTransformer
+metadata:
+  name: notImportantHere
+name: settings
+key: size
+annotation: example.com/settings-size
+`, rolloutInput, func(t *testing.T, err error) {
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if !strings.Contains(err.Error(), "has no key 'size'") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Give the containers of workloads default probes.  A probe
+// is only added to a container that lacks a probe of its kind,
+// so explicit probes are never overwritten.  If Containers is
+// empty, all containers get the probes, else only those named.
+type plugin struct {
+	ReadinessProbe map[string]interface{} `json:"readinessProbe,omitempty" yaml:"readinessProbe,omitempty"`
+	LivenessProbe  map[string]interface{} `json:"livenessProbe,omitempty" yaml:"livenessProbe,omitempty"`
+	StartupProbe   map[string]interface{} `json:"startupProbe,omitempty" yaml:"startupProbe,omitempty"`
+	Containers     []string               `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Target         *types.Selector        `json:"target,omitempty" yaml:"target,omitempty"`
+
+	probes []probe
+}
+
+// probe is a default probe and the container field holding it.
+type probe struct {
+	field string
+	node  *yaml.RNode
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.ReadinessProbe = nil
+	p.LivenessProbe = nil
+	p.StartupProbe = nil
+	p.Containers = nil
+	p.Target = nil
+	p.probes = nil
+	err = k8syaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	for _, pr := range []struct {
+		field string
+		value map[string]interface{}
+	}{
+		{"readinessProbe", p.ReadinessProbe},
+		{"livenessProbe", p.LivenessProbe},
+		{"startupProbe", p.StartupProbe},
+	} {
+		if len(pr.value) == 0 {
+			continue
+		}
+		node, err := yaml.FromMap(pr.value)
+		if err != nil {
+			return err
+		}
+		p.probes = append(p.probes, probe{field: pr.field, node: node})
+	}
+	if len(p.probes) == 0 {
+		return fmt.Errorf(
+			"must specify a readinessProbe, livenessProbe or startupProbe")
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) (err error) {
+	resources := m.Resources()
+	if p.Target != nil {
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		paths := resource.PodSpecPaths(r.GetKind())
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				return node, p.addProbes(node, paths)
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addProbes adds the default probes to the containers of the
+// pod specs at the paths.  Init containers don't take probes.
+func (p *plugin) addProbes(node *yaml.RNode, paths [][]string) error {
+	for _, path := range paths {
+		containers, err := node.Pipe(yaml.Lookup(append(path, "containers")...))
+		if err != nil {
+			return err
+		}
+		if containers == nil || containers.YNode().Kind != yaml.SequenceNode {
+			continue
+		}
+		err = containers.VisitElements(func(c *yaml.RNode) error {
+			if c.YNode().Kind != yaml.MappingNode || !p.selects(c) {
+				return nil
+			}
+			for _, pr := range p.probes {
+				if c.Field(pr.field) != nil {
+					continue
+				}
+				err := c.PipeE(yaml.SetField(pr.field, pr.node.Copy()))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selects returns true if the container should get the probes.
+func (p *plugin) selects(container *yaml.RNode) bool {
+	if len(p.Containers) == 0 {
+		return true
+	}
+	name := container.Field("name")
+	if name == nil || name.Value.YNode().Kind != yaml.ScalarNode {
+		return false
+	}
+	for _, n := range p.Containers {
+		if n == name.Value.YNode().Value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestContainerProbeTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ContainerProbeTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ContainerProbeTransformer
+metadata:
+  name: notImportantHere
+readinessProbe:
+  httpGet:
+    path: /healthz
+    port: 8080
+containers:
+- web
+- api
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+      - name: api
+        image: api
+        readinessProbe:
+          tcpSocket:
+            port: 9000
+      - name: sidecar
+        image: sidecar
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: web
+        name: web
+        readinessProbe:
+          httpGet:
+            path: /healthz
+            port: 8080
+      - image: api
+        name: api
+        readinessProbe:
+          tcpSocket:
+            port: 9000
+      - image: sidecar
+        name: sidecar
+`)
+}
+
+func TestContainerProbeTransformerNoProbes(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ContainerProbeTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: ContainerProbeTransformer
+metadata:
+  name: notImportantHere
+containers:
+- web
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+`, func(t *testing.T, err error) {
+		if err == nil {
+			t.Fatalf("expected an error")
+		}
+		if !strings.Contains(err.Error(), "must specify a readinessProbe") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Set the resource requests and limits of containers from a
+// table of container name to requests and limits, given inline
+// as Containers or in the file at Path, e.g. as exported from
+// capacity planning.  Only the requests and limits in the table
+// are set; the others of a container are left as they are.
+// Container names in the table that no workload has are logged
+// as warnings.
+type plugin struct {
+	h *resmap.PluginHelpers
+
+	Containers map[string]containerResources `json:"containers,omitempty" yaml:"containers,omitempty"`
+	Path       string                        `json:"path,omitempty" yaml:"path,omitempty"`
+	Target     *types.Selector               `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// containerResources are the requests and limits
+// of a container, by resource name.
+type containerResources struct {
+	Requests map[string]string `json:"requests,omitempty" yaml:"requests,omitempty"`
+	Limits   map[string]string `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.h = h
+	p.Containers = nil
+	p.Path = ""
+	p.Target = nil
+	err = k8syaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.Path != "" {
+		if len(p.Containers) > 0 {
+			return fmt.Errorf("cannot specify both containers and path")
+		}
+		content, err := p.h.Loader().Load(p.Path)
+		if err != nil {
+			return err
+		}
+		if err = k8syaml.Unmarshal(content, &p.Containers); err != nil {
+			return fmt.Errorf("parsing '%s': %v", p.Path, err)
+		}
+	}
+	if len(p.Containers) == 0 {
+		return fmt.Errorf("must specify containers or a path")
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) (err error) {
+	resources := m.Resources()
+	if p.Target != nil {
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	seen := make(map[string]bool)
+	for _, r := range resources {
+		paths := resource.PodSpecPaths(r.GetKind())
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				return node, p.setResources(node, paths, seen)
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	var unknown []string
+	for name := range p.Containers {
+		if !seen[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		log.Printf("no workload has a container named '%s'", name)
+	}
+	return nil
+}
+
+// setResources sets the requests and limits of the containers
+// of the pod specs at the paths that are in the table, and
+// records the names of all containers as seen.
+func (p *plugin) setResources(
+	node *yaml.RNode, paths [][]string, seen map[string]bool) error {
+	for _, path := range paths {
+		for _, field := range []string{"containers", "initContainers"} {
+			containers, err := node.Pipe(yaml.Lookup(append(path, field)...))
+			if err != nil {
+				return err
+			}
+			if containers == nil || containers.YNode().Kind != yaml.SequenceNode {
+				continue
+			}
+			err = containers.VisitElements(func(c *yaml.RNode) error {
+				name, err := c.Pipe(yaml.Lookup("name"))
+				if err != nil || name == nil {
+					return err
+				}
+				seen[yaml.GetValue(name)] = true
+				res, ok := p.Containers[yaml.GetValue(name)]
+				if !ok {
+					return nil
+				}
+				if err = setQuantities(c, "requests", res.Requests); err != nil {
+					return err
+				}
+				return setQuantities(c, "limits", res.Limits)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setQuantities sets the quantities at resources.<field>
+// of the container.
+func setQuantities(
+	container *yaml.RNode, field string, quantities map[string]string) error {
+	if len(quantities) == 0 {
+		return nil
+	}
+	node, err := container.Pipe(
+		yaml.LookupCreate(yaml.MappingNode, "resources", field))
+	if err != nil {
+		return err
+	}
+	for _, k := range yaml.SortedMapKeys(quantities) {
+		err = node.PipeE(yaml.SetField(k, yaml.NewStringRNode(quantities[k])))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
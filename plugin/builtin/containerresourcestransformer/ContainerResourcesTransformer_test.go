@@ -0,0 +1,129 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const resourcesInput = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: migrate
+        image: migrate
+      containers:
+      - name: web
+        image: web
+        resources:
+          requests:
+            cpu: 100m
+            ephemeral-storage: 1Gi
+      - name: sidecar
+        image: sidecar
+        resources:
+          limits:
+            memory: 64Mi
+`
+
+const resourcesOutput = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: web
+        name: web
+        resources:
+          limits:
+            memory: 512Mi
+          requests:
+            cpu: 250m
+            ephemeral-storage: 1Gi
+            memory: 256Mi
+      - image: sidecar
+        name: sidecar
+        resources:
+          limits:
+            memory: 64Mi
+      initContainers:
+      - image: migrate
+        name: migrate
+        resources:
+          limits:
+            cpu: "1"
+`
+
+func TestContainerResourcesTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ContainerResourcesTransformer")
+	defer th.Reset()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer func() {
+		log.SetOutput(os.Stderr)
+	}()
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ContainerResourcesTransformer
+metadata:
+  name: notImportantHere
+containers:
+  web:
+    requests:
+      cpu: 250m
+      memory: 256Mi
+    limits:
+      memory: 512Mi
+  migrate:
+    limits:
+      cpu: "1"
+  worker:
+    requests:
+      cpu: "2"
+`, resourcesInput, resourcesOutput)
+	if !strings.Contains(buf.String(), "no workload has a container named 'worker'") {
+		t.Fatalf("expected a warning, got: %s", buf.String())
+	}
+}
+
+func TestContainerResourcesTransformerFromFile(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ContainerResourcesTransformer")
+	defer th.Reset()
+
+	th.WriteF("capacity.yaml", `
+web:
+  requests:
+    cpu: 250m
+    memory: 256Mi
+  limits:
+    memory: 512Mi
+migrate:
+  limits:
+    cpu: "1"
+`)
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ContainerResourcesTransformer
+metadata:
+  name: notImportantHere
+path: capacity.yaml
+`, resourcesInput, resourcesOutput)
+}
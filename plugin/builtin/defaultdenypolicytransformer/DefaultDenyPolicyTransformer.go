@@ -0,0 +1,131 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultPolicyName = "default-deny"
+
+// Emit a NetworkPolicy denying all ingress for every namespace
+// the resources are in.  Though it makes new objects, this is a
+// transformer rather than a generator, since it must see the
+// namespaces the resources end up in.  Namespaces already
+// holding such a policy are left alone, as are resources with no
+// namespace, since which one they land in isn't known until
+// they're applied.
+type plugin struct {
+	rf *resource.Factory
+
+	// PolicyName is the name of each policy made.
+	// Defaults to default-deny.
+	PolicyName string `json:"policyName,omitempty" yaml:"policyName,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+var networkPolicyGvk = resid.Gvk{
+	Group:   "networking.k8s.io",
+	Version: "v1",
+	Kind:    "NetworkPolicy",
+}
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.PolicyName = ""
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.PolicyName == "" {
+		p.PolicyName = defaultPolicyName
+	}
+	p.rf = h.ResmapFactory().RF()
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	namespaces := make(map[string]bool)
+	for _, r := range m.Resources() {
+		gvk := r.GetGvk()
+		switch {
+		case gvk.Kind == "Namespace":
+			namespaces[r.GetName()] = true
+		case gvk.IsNamespaceableKind() && r.GetNamespace() != "":
+			namespaces[r.GetNamespace()] = true
+		}
+	}
+	for _, r := range m.Resources() {
+		if r.GetKind() == networkPolicyGvk.Kind && isDefaultDenyIngress(r) {
+			delete(namespaces, r.GetNamespace())
+		}
+	}
+	var sorted []string
+	for ns := range namespaces {
+		sorted = append(sorted, ns)
+	}
+	sort.Strings(sorted)
+	for _, ns := range sorted {
+		id := resid.NewResIdWithNamespace(networkPolicyGvk, p.PolicyName, ns)
+		if _, err := m.GetByCurrentId(id); err == nil {
+			// Respect a policy of that name that already exists.
+			continue
+		}
+		if err := m.Append(p.makePolicy(ns)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDefaultDenyIngress returns true if the NetworkPolicy selects
+// every pod in its namespace and allows them no ingress.
+func isDefaultDenyIngress(r *resource.Resource) bool {
+	if selector, err := r.GetFieldValue("spec.podSelector"); err != nil ||
+		!isEmptyMap(selector) {
+		return false
+	}
+	if rules, err := r.GetSlice("spec.ingress"); err == nil && len(rules) > 0 {
+		return false
+	}
+	types, err := r.GetSlice("spec.policyTypes")
+	if err != nil {
+		// Without policyTypes, a policy is about ingress.
+		return true
+	}
+	for _, t := range types {
+		if t == "Ingress" {
+			return true
+		}
+	}
+	return false
+}
+
+func isEmptyMap(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	return ok && len(m) == 0
+}
+
+func (p *plugin) makePolicy(namespace string) *resource.Resource {
+	return p.rf.FromMap(map[string]interface{}{
+		"apiVersion": networkPolicyGvk.ApiVersion(),
+		"kind":       networkPolicyGvk.Kind,
+		"metadata": map[string]interface{}{
+			"name":      p.PolicyName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"podSelector": map[string]interface{}{},
+			"policyTypes": []interface{}{"Ingress"},
+		},
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const defaultQuotaName = "default-quota"
+
+// Emit a ResourceQuota with the given limits for every
+// namespace the resources are in, or just for the given
+// namespace.  Like the DefaultDenyPolicyTransformer, this is
+// a transformer, since it must see the namespaces the
+// resources end up in.  Namespaces already holding a
+// ResourceQuota are left alone, and resources with no namespace
+// are skipped, as their namespace isn't known until applied.
+type plugin struct {
+	rf *resource.Factory
+
+	// QuotaName is the name of each quota made.
+	// Defaults to default-quota.
+	QuotaName string `json:"quotaName,omitempty" yaml:"quotaName,omitempty"`
+
+	// Namespace, if not empty, is the only namespace
+	// to get a quota.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+
+	// Hard holds the limits, e.g. "limits.cpu: 4",
+	// that become the spec.hard of the quotas.
+	Hard map[string]string `json:"hard,omitempty" yaml:"hard,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+var resourceQuotaGvk = resid.Gvk{Version: "v1", Kind: "ResourceQuota"}
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.QuotaName = ""
+	p.Namespace = ""
+	p.Hard = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if len(p.Hard) == 0 {
+		return fmt.Errorf("must specify hard limits")
+	}
+	if p.QuotaName == "" {
+		p.QuotaName = defaultQuotaName
+	}
+	p.rf = h.ResmapFactory().RF()
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	namespaces := make(map[string]bool)
+	if p.Namespace != "" {
+		namespaces[p.Namespace] = true
+	} else {
+		for _, r := range m.Resources() {
+			gvk := r.GetGvk()
+			switch {
+			case gvk.Kind == "Namespace":
+				namespaces[r.GetName()] = true
+			case gvk.IsNamespaceableKind() && r.GetNamespace() != "":
+				namespaces[r.GetNamespace()] = true
+			}
+		}
+	}
+	for _, r := range m.Resources() {
+		if r.GetKind() == resourceQuotaGvk.Kind {
+			delete(namespaces, r.CurId().EffectiveNamespace())
+		}
+	}
+	var sorted []string
+	for ns := range namespaces {
+		sorted = append(sorted, ns)
+	}
+	sort.Strings(sorted)
+	for _, ns := range sorted {
+		if err := m.Append(p.makeQuota(ns)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) makeQuota(namespace string) *resource.Resource {
+	hard := make(map[string]interface{})
+	for k, v := range p.Hard {
+		hard[k] = v
+	}
+	return p.rf.FromMap(map[string]interface{}{
+		"apiVersion": resourceQuotaGvk.ApiVersion(),
+		"kind":       resourceQuotaGvk.Kind,
+		"metadata": map[string]interface{}{
+			"name":      p.QuotaName,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"hard": hard,
+		},
+	})
+}
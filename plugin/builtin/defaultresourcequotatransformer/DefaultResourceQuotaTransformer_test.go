@@ -0,0 +1,288 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestDefaultResourceQuotaTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultResourceQuotaTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: DefaultResourceQuotaTransformer
+metadata:
+  name: notImportantHere
+hard:
+  limits.cpu: "4"
+  pods: "10"
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: team
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: team
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: api
+  namespace: team
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: team
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: reader
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: default-quota
+  namespace: team
+spec:
+  hard:
+    limits.cpu: "4"
+    pods: "10"
+`)
+}
+
+func TestDefaultResourceQuotaTransformerSkipsExisting(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultResourceQuotaTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: DefaultResourceQuotaTransformer
+metadata:
+  name: notImportantHere
+quotaName: quota
+hard:
+  pods: "10"
+`, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: custom
+  namespace: shop
+spec:
+  hard:
+    pods: "50"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: storage
+`, `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: shop
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: custom
+  namespace: shop
+spec:
+  hard:
+    pods: "50"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: storage
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: quota
+  namespace: storage
+spec:
+  hard:
+    pods: "10"
+`)
+}
+
+func TestDefaultResourceQuotaTransformerNamespace(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultResourceQuotaTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: DefaultResourceQuotaTransformer
+metadata:
+  name: notImportantHere
+namespace: team
+hard:
+  pods: "10"
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: storage
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: storage
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: default-quota
+  namespace: team
+spec:
+  hard:
+    pods: "10"
+`)
+}
+
+func TestDefaultResourceQuotaTransformerNoLimits(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultResourceQuotaTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: DefaultResourceQuotaTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+`,
+		func(t *testing.T, err error) {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), "must specify hard limits") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+}
+
+func TestDefaultResourceQuotaTransformerSkipsNoNamespace(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultResourceQuotaTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: DefaultResourceQuotaTransformer
+metadata:
+  name: notImportantHere
+hard:
+  pods: "10"
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: default
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: default
+---
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: default-quota
+  namespace: default
+spec:
+  hard:
+    pods: "10"
+`)
+}
+
+func TestDefaultResourceQuotaTransformerExistingInDefault(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultResourceQuotaTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: DefaultResourceQuotaTransformer
+metadata:
+  name: notImportantHere
+hard:
+  pods: "10"
+`, `
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: custom
+spec:
+  hard:
+    pods: "50"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: default
+`, `
+apiVersion: v1
+kind: ResourceQuota
+metadata:
+  name: custom
+spec:
+  hard:
+    pods: "50"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  namespace: default
+`)
+}
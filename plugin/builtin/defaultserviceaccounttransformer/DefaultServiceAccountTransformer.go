@@ -0,0 +1,139 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Set the serviceAccountName of the pods of workloads that
+// don't name a service account, so that they don't run as the
+// default account of their namespace.  If Create is true, the
+// ServiceAccount is also made in each namespace of those
+// workloads that lacks it.
+type plugin struct {
+	rf *resource.Factory
+
+	ServiceAccountName string          `json:"serviceAccountName,omitempty" yaml:"serviceAccountName,omitempty"`
+	Create             bool            `json:"create,omitempty" yaml:"create,omitempty"`
+	Target             *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+var serviceAccountGvk = resid.Gvk{Version: "v1", Kind: "ServiceAccount"}
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.ServiceAccountName = ""
+	p.Create = false
+	p.Target = nil
+	err = k8syaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.ServiceAccountName == "" {
+		return fmt.Errorf("must specify serviceAccountName")
+	}
+	p.rf = h.ResmapFactory().RF()
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) (err error) {
+	resources := m.Resources()
+	if p.Target != nil {
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	namespaces := make(map[string]bool)
+	for _, r := range resources {
+		paths := resource.PodSpecPaths(r.GetKind())
+		set := false
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				set, err = p.setServiceAccount(node, paths)
+				return node, err
+			})))
+		if err != nil {
+			return err
+		}
+		if set {
+			namespaces[r.GetNamespace()] = true
+		}
+	}
+	if !p.Create {
+		return nil
+	}
+	var sorted []string
+	for ns := range namespaces {
+		sorted = append(sorted, ns)
+	}
+	sort.Strings(sorted)
+	for _, ns := range sorted {
+		id := resid.NewResIdWithNamespace(serviceAccountGvk, p.ServiceAccountName, ns)
+		if _, err := m.GetByCurrentId(id); err == nil {
+			continue
+		}
+		if err := m.Append(p.makeServiceAccount(ns)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setServiceAccount sets the serviceAccountName of the pod specs
+// at the paths that name no service account, and returns true
+// if it set any.
+func (p *plugin) setServiceAccount(
+	node *yaml.RNode, paths [][]string) (bool, error) {
+	set := false
+	for _, path := range paths {
+		podSpec, err := node.Pipe(yaml.Lookup(path...))
+		if err != nil {
+			return false, err
+		}
+		if podSpec == nil || podSpec.YNode().Kind != yaml.MappingNode {
+			continue
+		}
+		// serviceAccount is the deprecated form of serviceAccountName.
+		if podSpec.Field("serviceAccountName") != nil ||
+			podSpec.Field("serviceAccount") != nil {
+			continue
+		}
+		err = podSpec.PipeE(yaml.SetField(
+			"serviceAccountName", yaml.NewStringRNode(p.ServiceAccountName)))
+		if err != nil {
+			return false, err
+		}
+		set = true
+	}
+	return set, nil
+}
+
+func (p *plugin) makeServiceAccount(namespace string) *resource.Resource {
+	meta := map[string]interface{}{
+		"name": p.ServiceAccountName,
+	}
+	if namespace != "" {
+		meta["namespace"] = namespace
+	}
+	return p.rf.FromMap(map[string]interface{}{
+		"apiVersion": serviceAccountGvk.ApiVersion(),
+		"kind":       serviceAccountGvk.Kind,
+		"metadata":   meta,
+	})
+}
@@ -0,0 +1,176 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const serviceAccountInput = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: shop
+spec:
+  template:
+    spec:
+      serviceAccountName: migrator
+      containers:
+      - name: migrate
+        image: migrate
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: shop
+`
+
+func TestDefaultServiceAccountTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultServiceAccountTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: DefaultServiceAccountTransformer
+metadata:
+  name: notImportantHere
+serviceAccountName: restricted
+`, serviceAccountInput, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  template:
+    spec:
+      containers:
+      - image: web
+        name: web
+      serviceAccountName: restricted
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: shop
+spec:
+  template:
+    spec:
+      containers:
+      - image: migrate
+        name: migrate
+      serviceAccountName: migrator
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: shop
+`)
+}
+
+func TestDefaultServiceAccountTransformerCreate(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("DefaultServiceAccountTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: DefaultServiceAccountTransformer
+metadata:
+  name: notImportantHere
+serviceAccountName: restricted
+create: true
+`, serviceAccountInput+`
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+  namespace: ops
+spec:
+  containers:
+  - name: debug
+    image: busybox
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: restricted
+  namespace: ops
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  template:
+    spec:
+      containers:
+      - image: web
+        name: web
+      serviceAccountName: restricted
+---
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  namespace: shop
+spec:
+  template:
+    spec:
+      containers:
+      - image: migrate
+        name: migrate
+      serviceAccountName: migrator
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  namespace: shop
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+  namespace: ops
+spec:
+  containers:
+  - image: busybox
+    name: debug
+  serviceAccountName: restricted
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: restricted
+  namespace: ops
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: restricted
+  namespace: shop
+`)
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate an ExternalSecret, from which the External Secrets
+// Operator makes a Secret holding data read from a store.
+type plugin struct {
+	h *resmap.PluginHelpers
+	types.ExternalSecretArgs
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(h *resmap.PluginHelpers, config []byte) (err error) {
+	p.ExternalSecretArgs = types.ExternalSecretArgs{}
+	err = yaml.Unmarshal(config, p)
+	if err != nil {
+		return err
+	}
+	if p.Name == "" {
+		return fmt.Errorf("must specify a name")
+	}
+	if p.SecretStoreRef.Name == "" {
+		return fmt.Errorf(
+			"must specify the name of the secretStoreRef of '%s'", p.Name)
+	}
+	if len(p.Data) == 0 {
+		return fmt.Errorf("must specify data of '%s'", p.Name)
+	}
+	for i, d := range p.Data {
+		if d.SecretKey == "" || d.RemoteKey == "" {
+			return fmt.Errorf(
+				"data entry %d of '%s' must specify both secretKey and remoteKey",
+				i, p.Name)
+		}
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	meta := map[string]interface{}{"name": p.Name}
+	if p.Namespace != "" {
+		meta["namespace"] = p.Namespace
+	}
+	storeRef := map[string]interface{}{"name": p.SecretStoreRef.Name}
+	if p.SecretStoreRef.Kind != "" {
+		storeRef["kind"] = p.SecretStoreRef.Kind
+	}
+	target := p.Target
+	if target == "" {
+		target = p.Name
+	}
+	var data []interface{}
+	for _, d := range p.Data {
+		remoteRef := map[string]interface{}{"key": d.RemoteKey}
+		if d.Property != "" {
+			remoteRef["property"] = d.Property
+		}
+		data = append(data, map[string]interface{}{
+			"secretKey": d.SecretKey,
+			"remoteRef": remoteRef,
+		})
+	}
+	spec := map[string]interface{}{
+		"secretStoreRef": storeRef,
+		"target":         map[string]interface{}{"name": target},
+		"data":           data,
+	}
+	if p.RefreshInterval != "" {
+		spec["refreshInterval"] = p.RefreshInterval
+	}
+	return p.h.ResmapFactory().FromResource(
+		p.h.ResmapFactory().RF().FromMap(map[string]interface{}{
+			"apiVersion": "external-secrets.io/v1",
+			"kind":       "ExternalSecret",
+			"metadata":   meta,
+			"spec":       spec,
+		})), nil
+}
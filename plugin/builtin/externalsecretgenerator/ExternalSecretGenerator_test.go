@@ -0,0 +1,59 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestExternalSecretGenerator(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ExternalSecretGenerator")
+	defer th.Reset()
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: ExternalSecretGenerator
+metadata:
+  name: notImportantHere
+name: db-credentials
+namespace: shop
+secretStoreRef:
+  name: vault
+  kind: ClusterSecretStore
+target: db
+refreshInterval: 1h
+data:
+- secretKey: username
+  remoteKey: shop/db
+  property: user
+- secretKey: password
+  remoteKey: shop/db-password
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: external-secrets.io/v1
+kind: ExternalSecret
+metadata:
+  name: db-credentials
+  namespace: shop
+spec:
+  data:
+  - remoteRef:
+      key: shop/db
+      property: user
+    secretKey: username
+  - remoteRef:
+      key: shop/db-password
+    secretKey: password
+  refreshInterval: 1h
+  secretStoreRef:
+    kind: ClusterSecretStore
+    name: vault
+  target:
+    name: db
+`)
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate objects by executing a Go text/template against
+// a map of values.
+type plugin struct {
+	h *resmap.PluginHelpers
+	types.GoTemplateArgs
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(h *resmap.PluginHelpers, config []byte) (err error) {
+	p.GoTemplateArgs = types.GoTemplateArgs{}
+	err = yaml.Unmarshal(config, p)
+	if err != nil {
+		return err
+	}
+	if p.Template == "" {
+		return fmt.Errorf("must specify a template in\n%s", string(config))
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	content, err := p.h.Loader().Load(p.Template)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(p.Template).
+		Funcs(template.FuncMap{"now": p.h.Now}).
+		Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template '%s': %v", p.Template, err)
+	}
+	var out bytes.Buffer
+	if err = t.Execute(&out, p.Values); err != nil {
+		return nil, fmt.Errorf("executing template '%s': %v", p.Template, err)
+	}
+	m, err := p.h.ResmapFactory().NewResMapFromBytes(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf(
+			"output of template '%s': %v", p.Template, err)
+	}
+	return m, nil
+}
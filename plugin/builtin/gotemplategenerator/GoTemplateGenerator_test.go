@@ -0,0 +1,57 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestGoTemplateGenerator(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("GoTemplateGenerator")
+	defer th.Reset()
+
+	th.WriteF("queue.yaml.tmpl", `
+{{- range .queues }}
+---
+apiVersion: example.com/v1
+kind: Queue
+metadata:
+  name: {{ . }}
+spec:
+  retries: {{ $.retries }}
+{{- end }}
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: GoTemplateGenerator
+metadata:
+  name: notImportantHere
+template: queue.yaml.tmpl
+values:
+  retries: 3
+  queues:
+  - orders
+  - invoices
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: example.com/v1
+kind: Queue
+metadata:
+  name: orders
+spec:
+  retries: 3
+---
+apiVersion: example.com/v1
+kind: Queue
+metadata:
+  name: invoices
+spec:
+  retries: 3
+`)
+}
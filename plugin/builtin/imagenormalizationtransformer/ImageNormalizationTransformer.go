@@ -0,0 +1,95 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/image"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Rewrite container image references to their fully
+// qualified form, e.g. "nginx" to "docker.io/library/nginx",
+// so that tools scanning image fields needn't guess the
+// registry.  Used as an entry in the transformers field,
+// it runs after the images field has pinned tags.
+type plugin struct {
+	// When true, images with the latest tag, explicitly or
+	// because they have neither tag nor digest, are an error.
+	DisallowLatest bool `json:"disallowLatest,omitempty" yaml:"disallowLatest,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// Fields holding lists of containers, wherever they appear.
+var containerListFields = map[string]bool{
+	"containers":          true,
+	"initContainers":      true,
+	"ephemeralContainers": true,
+}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.DisallowLatest = false
+	return k8syaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		if r.GetKind() == "CustomResourceDefinition" {
+			continue
+		}
+		err := r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				return node, p.walk(r, node)
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walk normalizes the images of the containers in every list
+// of containers found below the given node.
+func (p *plugin) walk(r *resource.Resource, node *yaml.RNode) error {
+	switch node.YNode().Kind {
+	case yaml.MappingNode:
+		return node.VisitFields(func(n *yaml.MapNode) error {
+			if containerListFields[n.Key.YNode().Value] &&
+				n.Value.YNode().Kind == yaml.SequenceNode {
+				return n.Value.VisitElements(func(c *yaml.RNode) error {
+					return p.normalize(r, c)
+				})
+			}
+			return p.walk(r, n.Value)
+		})
+	case yaml.SequenceNode:
+		return node.VisitElements(func(n *yaml.RNode) error {
+			return p.walk(r, n)
+		})
+	}
+	return nil
+}
+
+func (p *plugin) normalize(r *resource.Resource, container *yaml.RNode) error {
+	img := container.Field("image")
+	if img == nil || img.Value.YNode().Kind != yaml.ScalarNode {
+		return nil
+	}
+	name := img.Value.YNode().Value
+	if p.DisallowLatest && image.IsLatest(name) {
+		return fmt.Errorf(
+			"resource %s uses image '%s' with the latest tag", r.CurId(), name)
+	}
+	img.Value.YNode().Value = image.Normalize(name)
+	return nil
+}
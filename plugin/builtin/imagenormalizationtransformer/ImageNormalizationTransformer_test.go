@@ -0,0 +1,85 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestImageNormalizationTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ImageNormalizationTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ImageNormalizationTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: me/init:v1
+      containers:
+      - name: nginx
+        image: nginx
+      - name: sidecar
+        image: gcr.io/project/sidecar@sha256:abcd
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: docker.io/library/nginx
+        name: nginx
+      - image: gcr.io/project/sidecar@sha256:abcd
+        name: sidecar
+      initContainers:
+      - image: docker.io/me/init:v1
+        name: init
+`)
+}
+
+func TestImageNormalizationTransformerDisallowLatest(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ImageNormalizationTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: ImageNormalizationTransformer
+metadata:
+  name: notImportantHere
+disallowLatest: true
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containers:
+  - name: nginx
+    image: nginx:latest
+`, func(t *testing.T, err error) {
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+		if err.Error() != "resource ~G_v1_Pod|~X|pod uses image 'nginx:latest' with the latest tag" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
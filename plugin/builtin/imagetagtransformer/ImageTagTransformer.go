@@ -5,7 +5,10 @@
 package main
 
 import (
+	"fmt"
+
 	"sigs.k8s.io/kustomize/api/filters/imagetag"
+	"sigs.k8s.io/kustomize/api/image"
 	"sigs.k8s.io/kustomize/api/resmap"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/yaml"
@@ -25,7 +28,36 @@ func (p *plugin) Config(
 	_ *resmap.PluginHelpers, c []byte) (err error) {
 	p.ImageTag = types.Image{}
 	p.FieldSpecs = nil
-	return yaml.Unmarshal(c, p)
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	return validateImage(p.ImageTag)
+}
+
+// validateImage returns an error naming the first field of
+// the image entry that doesn't hold a well formed value.
+func validateImage(img types.Image) error {
+	for _, f := range []struct {
+		field    string
+		value    string
+		validate func(string) error
+	}{
+		{"name", img.Name, image.ValidateName},
+		{"newName", img.NewName, image.ValidateName},
+		{"newTag", img.NewTag, image.ValidateTag},
+		{"digest", img.Digest, image.ValidateDigest},
+		{"newDigest", img.NewDigest, image.ValidateDigest},
+	} {
+		if f.value == "" {
+			continue
+		}
+		if err := f.validate(f.value); err != nil {
+			return fmt.Errorf(
+				"image '%s' has malformed %s: %v", img.Name, f.field, err)
+		}
+	}
+	return nil
 }
 
 func (p *plugin) Transform(m resmap.ResMap) error {
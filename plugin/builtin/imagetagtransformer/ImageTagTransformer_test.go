@@ -518,7 +518,7 @@ spec:
     name: my-image
   initContainers:
   - image: old-image-name
-    name: my-image
+    name: my-init
   template:
     spec:
       containers:
@@ -526,7 +526,7 @@ spec:
         name: my-image
       initContainers:
       - image: old-image-name
-        name: my-image
+        name: my-init
 `)
 
 	m := th.Run("/app", th.MakeDefaultOptions())
@@ -542,7 +542,7 @@ spec:
     name: my-image
   initContainers:
   - image: new-image-name:new-tag
-    name: my-image
+    name: my-init
   template:
     spec:
       containers:
@@ -550,6 +550,6 @@ spec:
         name: my-image
       initContainers:
       - image: new-image-name:new-tag
-        name: my-image
+        name: my-init
 `)
 }
@@ -8,3 +8,4 @@ require (
 )
 
 replace sigs.k8s.io/kustomize/kyaml => ../../../kyaml
+replace sigs.k8s.io/kustomize/api => ../../../api
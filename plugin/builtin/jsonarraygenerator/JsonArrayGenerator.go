@@ -0,0 +1,166 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Generate objects by rendering a template once
+// for each element of a JSON array.
+type plugin struct {
+	h *resmap.PluginHelpers
+	types.JsonArrayArgs
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+var (
+	fieldRefRegex     = regexp.MustCompile(`{{\s*\.([^{}\s]+)\s*}}`)
+	loneFieldRefRegex = regexp.MustCompile(`^{{\s*\.([^{}\s]+)\s*}}$`)
+)
+
+func (p *plugin) Config(h *resmap.PluginHelpers, config []byte) (err error) {
+	p.JsonArrayArgs = types.JsonArrayArgs{}
+	err = yaml.Unmarshal(config, p)
+	if err != nil {
+		return err
+	}
+	if p.Source == "" || p.Template == "" {
+		return fmt.Errorf(
+			"must specify both source and template in\n%s", string(config))
+	}
+	p.h = h
+	return nil
+}
+
+func (p *plugin) Generate() (resmap.ResMap, error) {
+	content, err := p.h.Loader().Load(p.Source)
+	if err != nil {
+		return nil, err
+	}
+	var elements []interface{}
+	if err = json.Unmarshal(content, &elements); err != nil {
+		return nil, fmt.Errorf(
+			"source '%s' must hold a JSON array: %v", p.Source, err)
+	}
+	template, err := p.h.Loader().Load(p.Template)
+	if err != nil {
+		return nil, err
+	}
+	rf := p.h.ResmapFactory().RF()
+	templates, err := rf.SliceFromBytes(template)
+	if err != nil {
+		return nil, fmt.Errorf("template '%s': %v", p.Template, err)
+	}
+	result := resmap.New()
+	for i, e := range elements {
+		fields, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf(
+				"element %d of JSON array in '%s' is not an object", i, p.Source)
+		}
+		for _, t := range templates {
+			m, err := t.Map()
+			if err != nil {
+				return nil, err
+			}
+			rendered, err := render(m, fields)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"rendering template '%s' with element %d of '%s': %v",
+					p.Template, i, p.Source, err)
+			}
+			err = result.Append(rf.FromMap(rendered.(map[string]interface{})))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// render returns a copy of the given template value
+// with all field references replaced.
+func render(v interface{}, fields map[string]interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(x))
+		for k, val := range x {
+			rendered, err := render(val, fields)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = rendered
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(x))
+		for i, val := range x {
+			rendered, err := render(val, fields)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = rendered
+		}
+		return result, nil
+	case string:
+		return renderString(x, fields)
+	default:
+		return v, nil
+	}
+}
+
+func renderString(s string, fields map[string]interface{}) (interface{}, error) {
+	if m := loneFieldRefRegex.FindStringSubmatch(s); m != nil {
+		// Keep the type of the field, e.g. so that a port remains a number.
+		return lookup(m[1], fields)
+	}
+	var err error
+	result := fieldRefRegex.ReplaceAllStringFunc(s, func(ref string) string {
+		if err != nil {
+			return ""
+		}
+		var v interface{}
+		v, err = lookup(fieldRefRegex.FindStringSubmatch(ref)[1], fields)
+		return fmt.Sprintf("%v", v)
+	})
+	return result, err
+}
+
+// lookup returns the scalar value at the given dot-separated path.
+func lookup(path string, fields map[string]interface{}) (interface{}, error) {
+	var v interface{} = fields
+	for _, f := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("field '%s' not found", path)
+		}
+		if v, ok = m[f]; !ok {
+			return nil, fmt.Errorf("field '%s' not found", path)
+		}
+	}
+	switch x := v.(type) {
+	case map[string]interface{}, []interface{}:
+		return nil, fmt.Errorf("field '%s' is not a scalar", path)
+	case float64:
+		// JSON numbers decode to float64; don't render 80 as 80.0.
+		// Beyond 2^53 a float64 can't hold every integer, nor
+		// convert to int64 reliably, so such values stay floats.
+		if x == math.Trunc(x) && math.Abs(x) < 1<<53 {
+			return int64(x), nil
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestJsonArrayGenerator(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("JsonArrayGenerator")
+	defer th.Reset()
+
+	th.WriteF("queues.json", `[
+  {"name": "orders", "limits": {"retries": 3}, "dlq": true},
+  {"name": "invoices", "limits": {"retries": 5}, "dlq": false}
+]`)
+	th.WriteF("queue.yaml", `
+apiVersion: example.com/v1
+kind: Queue
+metadata:
+  name: "{{ .name }}"
+spec:
+  description: "queue {{ .name }}, {{ .limits.retries }} retries"
+  retries: "{{ .limits.retries }}"
+  deadLetter: "{{ .dlq }}"
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: JsonArrayGenerator
+metadata:
+  name: notImportantHere
+source: queues.json
+template: queue.yaml
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: example.com/v1
+kind: Queue
+metadata:
+  name: orders
+spec:
+  deadLetter: true
+  description: queue orders, 3 retries
+  retries: 3
+---
+apiVersion: example.com/v1
+kind: Queue
+metadata:
+  name: invoices
+spec:
+  deadLetter: false
+  description: queue invoices, 5 retries
+  retries: 5
+`)
+}
+
+func TestJsonArrayGeneratorLargeNumbers(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("JsonArrayGenerator")
+	defer th.Reset()
+
+	th.WriteF("limits.json", `[
+  {"name": "big", "bytes": 1e20, "count": 9007199254740991}
+]`)
+	th.WriteF("limit.yaml", `
+apiVersion: example.com/v1
+kind: Limit
+metadata:
+  name: "{{ .name }}"
+spec:
+  bytes: "{{ .bytes }}"
+  count: "{{ .count }}"
+`)
+
+	rm := th.LoadAndRunGenerator(`
+apiVersion: builtin
+kind: JsonArrayGenerator
+metadata:
+  name: notImportantHere
+source: limits.json
+template: limit.yaml
+`)
+
+	th.AssertActualEqualsExpected(rm, `
+apiVersion: example.com/v1
+kind: Limit
+metadata:
+  name: big
+spec:
+  bytes: 1e+20
+  count: 9007199254740991
+`)
+}
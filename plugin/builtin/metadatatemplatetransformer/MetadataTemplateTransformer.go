@@ -0,0 +1,141 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Add annotations and labels whose values are computed from
+// the fields of the resource they are added to, e.g.
+//
+//   annotations:
+//     app: "{{ .metadata.name }}"
+//
+// The template language is deliberately restricted: the only
+// thing allowed between braces is a dot-separated field path.
+type plugin struct {
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Target      *types.Selector   `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+var (
+	templateActionRegex = regexp.MustCompile(`{{(.*?)}}`)
+	fieldPathRegex      = regexp.MustCompile(`^(\.[^.\s{}]+)+$`)
+)
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Annotations = nil
+	p.Labels = nil
+	p.Target = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	for _, m := range []map[string]string{p.Annotations, p.Labels} {
+		for k, v := range m {
+			if err = validateTemplate(v); err != nil {
+				return fmt.Errorf("template for key '%s': %v", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) (err error) {
+	resources := m.Resources()
+	if p.Target != nil {
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		if len(p.Annotations) > 0 {
+			annotations, err := expandAll(r, p.Annotations, r.GetAnnotations())
+			if err != nil {
+				return err
+			}
+			r.SetAnnotations(annotations)
+		}
+		if len(p.Labels) > 0 {
+			labels, err := expandAll(r, p.Labels, r.GetLabels())
+			if err != nil {
+				return err
+			}
+			r.SetLabels(labels)
+		}
+	}
+	return nil
+}
+
+// expandAll evaluates each template against the resource,
+// storing the results in the given map under the template's key.
+func expandAll(
+	r *resource.Resource, templates map[string]string,
+	result map[string]string) (map[string]string, error) {
+	if result == nil {
+		result = make(map[string]string)
+	}
+	for k, t := range templates {
+		v, err := expand(r, t)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"evaluating template for key '%s' on %s: %v", k, r.CurId(), err)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+func expand(r *resource.Resource, t string) (string, error) {
+	var err error
+	result := templateActionRegex.ReplaceAllStringFunc(t, func(action string) string {
+		if err != nil {
+			return ""
+		}
+		path := strings.TrimPrefix(
+			strings.TrimSpace(action[2:len(action)-2]), ".")
+		var v interface{}
+		v, err = r.GetFieldValue(path)
+		if err != nil {
+			return ""
+		}
+		switch v.(type) {
+		case map[string]interface{}, []interface{}:
+			err = fmt.Errorf("field '%s' is not a scalar", path)
+			return ""
+		}
+		return fmt.Sprintf("%v", v)
+	})
+	return result, err
+}
+
+func validateTemplate(t string) error {
+	for _, action := range templateActionRegex.FindAllStringSubmatch(t, -1) {
+		path := strings.TrimSpace(action[1])
+		if !fieldPathRegex.MatchString(path) {
+			return fmt.Errorf(
+				"'%s' is not a field path such as '.metadata.name'", path)
+		}
+	}
+	rest := templateActionRegex.ReplaceAllString(t, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return fmt.Errorf("unbalanced braces in '%s'", t)
+	}
+	return nil
+}
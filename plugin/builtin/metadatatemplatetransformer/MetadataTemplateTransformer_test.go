@@ -0,0 +1,138 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+const templateTestResources = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: myDeployment
+  labels:
+    tier: backend
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`
+
+func TestMetadataTemplateTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("MetadataTemplateTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: MetadataTemplateTransformer
+metadata:
+  name: notImportantHere
+annotations:
+  app: "{{ .metadata.name }}"
+  description: "{{.kind}} {{ .metadata.name }}"
+`, templateTestResources, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  annotations:
+    app: myDeployment
+    description: Deployment myDeployment
+  labels:
+    tier: backend
+  name: myDeployment
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    app: myService
+    description: Service myService
+  name: myService
+`)
+}
+
+func TestMetadataTemplateTransformerLabelsWithTarget(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("MetadataTemplateTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: MetadataTemplateTransformer
+metadata:
+  name: notImportantHere
+labels:
+  replicas: "r{{ .spec.replicas }}"
+target:
+  kind: Deployment
+`, templateTestResources, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    replicas: r3
+    tier: backend
+  name: myDeployment
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: myService
+`)
+}
+
+func TestMetadataTemplateTransformerMissingField(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("MetadataTemplateTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: MetadataTemplateTransformer
+metadata:
+  name: notImportantHere
+annotations:
+  count: "{{ .spec.replicas }}"
+`, templateTestResources, func(t *testing.T, err error) {
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+		if !strings.Contains(err.Error(), "spec.replicas") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestMetadataTemplateTransformerRestrictedSyntax(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("MetadataTemplateTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: MetadataTemplateTransformer
+metadata:
+  name: notImportantHere
+annotations:
+  app: "{{ printf \"%s\" .metadata.name }}"
+`, templateTestResources)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "is not a field path") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Move one resource to another namespace, updating the
+// references to it that name its namespace, i.e. RoleBinding
+// subjects for a ServiceAccount, and webhook, APIService and
+// conversion webhook references for a Service.  Resources left
+// in its old namespace that may refer to it by name alone
+// can't follow it, so they're logged as warnings.
+type plugin struct {
+	Target    *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+	Namespace string          `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+// Paths of the references to Services, by referring kind.
+var serviceRefPaths = map[string][][]string{
+	"ValidatingWebhookConfiguration": {{"webhooks", "[]", "clientConfig", "service"}},
+	"MutatingWebhookConfiguration":   {{"webhooks", "[]", "clientConfig", "service"}},
+	"APIService":                     {{"spec", "service"}},
+	"CustomResourceDefinition": {
+		{"spec", "conversion", "webhook", "clientConfig", "service"}},
+}
+
+// Paths of the references to ServiceAccounts, by referring kind.
+var serviceAccountRefPaths = map[string][][]string{
+	"RoleBinding":        {{"subjects", "[]"}},
+	"ClusterRoleBinding": {{"subjects", "[]"}},
+}
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Target = nil
+	p.Namespace = ""
+	err = k8syaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.Target == nil {
+		return fmt.Errorf("must specify a target")
+	}
+	if p.Namespace == "" {
+		return fmt.Errorf("must specify a namespace")
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	selected, err := m.Select(*p.Target)
+	if err != nil {
+		return err
+	}
+	if len(selected) != 1 {
+		return fmt.Errorf(
+			"target matches %d resources; expected one", len(selected))
+	}
+	moved := selected[0]
+	oldId := moved.CurId()
+	if !oldId.IsNamespaceableKind() {
+		return fmt.Errorf("cannot move cluster scoped %s", oldId)
+	}
+	newId := resid.NewResIdWithNamespace(oldId.Gvk, oldId.Name, p.Namespace)
+	if newId.IsNsEquals(oldId) {
+		return nil
+	}
+	if _, err := m.GetByCurrentId(newId); err == nil {
+		return fmt.Errorf("cannot move %s, since %s exists", oldId, newId)
+	}
+	moved.SetNamespace(p.Namespace)
+
+	var refPaths map[string][][]string
+	switch oldId.Kind {
+	case "Service":
+		refPaths = serviceRefPaths
+	case "ServiceAccount":
+		refPaths = serviceAccountRefPaths
+	}
+	for _, r := range m.Resources() {
+		if r == moved {
+			continue
+		}
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				for _, path := range refPaths[r.GetKind()] {
+					err := updateRefs(node, path, oldId, p.Namespace)
+					if err != nil {
+						return nil, err
+					}
+				}
+				return node, nil
+			})))
+		if err != nil {
+			return err
+		}
+		if r.CurId().IsNsEquals(oldId) {
+			warnOnNameRefs(r, oldId, p.Namespace)
+		}
+	}
+	return nil
+}
+
+// updateRefs sets the namespace of the references, at the
+// path in node, to the resource with the old id.  A reference
+// is a map holding a name and namespace, and a kind too if it
+// may refer to other kinds.
+func updateRefs(
+	node *yaml.RNode, path []string, oldId resid.ResId, namespace string) error {
+	if len(path) > 0 && path[0] == "[]" {
+		if node.YNode().Kind != yaml.SequenceNode {
+			return nil
+		}
+		return node.VisitElements(func(e *yaml.RNode) error {
+			return updateRefs(e, path[1:], oldId, namespace)
+		})
+	}
+	if len(path) > 0 {
+		field, err := node.Pipe(yaml.Lookup(path[0]))
+		if err != nil || field == nil {
+			return err
+		}
+		return updateRefs(field, path[1:], oldId, namespace)
+	}
+	if node.YNode().Kind != yaml.MappingNode ||
+		scalarField(node, "name") != oldId.Name {
+		return nil
+	}
+	if kind := scalarField(node, "kind"); kind != "" && kind != oldId.Kind {
+		return nil
+	}
+	refId := resid.NewResIdWithNamespace(
+		oldId.Gvk, oldId.Name, scalarField(node, "namespace"))
+	if !refId.IsNsEquals(oldId) {
+		return nil
+	}
+	return node.PipeE(yaml.SetField("namespace", yaml.NewStringRNode(namespace)))
+}
+
+func scalarField(node *yaml.RNode, field string) string {
+	f := node.Field(field)
+	if f == nil || f.Value.YNode().Kind != yaml.ScalarNode {
+		return ""
+	}
+	return f.Value.YNode().Value
+}
+
+// warnOnNameRefs logs the fields of the resource, outside of
+// its metadata, that look like they refer to the moved resource
+// by name, e.g. a pod's serviceAccountName.  The references
+// updated now name the new namespace, so they don't count.
+func warnOnNameRefs(
+	r *resource.Resource, oldId resid.ResId, namespace string) {
+	var paths []string
+	_ = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+		func(node *yaml.RNode) (*yaml.RNode, error) {
+			paths = findNameRefs(node, nil, oldId)
+			return node, nil
+		})))
+	for _, path := range paths {
+		log.Printf(
+			"%s may refer by name at %s to %s, which moved to namespace '%s'",
+			r.CurId(), path, oldId, namespace)
+	}
+}
+
+// findNameRefs returns the paths of the fields called name,
+// or ending in Name, that hold the name of the resource with
+// the given id.  The name of a map that names another kind or
+// namespace than that of the id doesn't count.
+func findNameRefs(
+	node *yaml.RNode, path []string, id resid.ResId) (result []string) {
+	n := node.YNode()
+	switch n.Kind {
+	case yaml.SequenceNode:
+		for i, e := range n.Content {
+			result = append(result, findNameRefs(yaml.NewRNode(e),
+				append(path, "["+strconv.Itoa(i)+"]"), id)...)
+		}
+	case yaml.MappingNode:
+		other := false
+		if kind := scalarField(node, "kind"); kind != "" && kind != id.Kind {
+			other = true
+		}
+		ns := scalarField(node, "namespace")
+		if ns != "" && !resid.NewResIdWithNamespace(id.Gvk, id.Name, ns).IsNsEquals(id) {
+			other = true
+		}
+		for i := 0; i < len(n.Content); i += 2 {
+			key, value := n.Content[i].Value, n.Content[i+1]
+			if len(path) == 0 && key == "metadata" {
+				continue
+			}
+			if value.Kind == yaml.ScalarNode {
+				if !other && value.Value == id.Name &&
+					(key == "name" || strings.HasSuffix(key, "Name")) {
+					result = append(result, joinPath(append(path, key)))
+				}
+				continue
+			}
+			result = append(result, findNameRefs(yaml.NewRNode(value),
+				append(path, key), id)...)
+		}
+	}
+	return result
+}
+
+func joinPath(path []string) string {
+	return strings.Replace(strings.Join(path, "."), ".[", "[", -1)
+}
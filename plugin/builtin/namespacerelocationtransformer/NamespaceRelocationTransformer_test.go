@@ -0,0 +1,202 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestNamespaceRelocationTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceRelocationTransformer")
+	defer th.Reset()
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: NamespaceRelocationTransformer
+metadata:
+  name: notImportantHere
+target:
+  kind: ServiceAccount
+  name: deployer
+namespace: ci
+`, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: deployer
+  namespace: apps
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: deployer
+  namespace: apps
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: deployer
+subjects:
+- kind: ServiceAccount
+  name: deployer
+  namespace: apps
+- kind: ServiceAccount
+  name: deployer
+  namespace: other
+- kind: User
+  name: deployer
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: deploy
+  namespace: apps
+spec:
+  serviceAccountName: deployer
+`, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: deployer
+  namespace: ci
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: deployer
+  namespace: apps
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: deployer
+subjects:
+- kind: ServiceAccount
+  name: deployer
+  namespace: ci
+- kind: ServiceAccount
+  name: deployer
+  namespace: other
+- kind: User
+  name: deployer
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: deploy
+  namespace: apps
+spec:
+  serviceAccountName: deployer
+`)
+	// The Role of the roleRef, the User and the account in another
+	// namespace have the same name, but only the pod is a concern.
+	var warnings []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.Contains(line, "may refer by name") {
+			warnings = append(warnings, line)
+		}
+	}
+	if len(warnings) != 1 || !strings.HasSuffix(warnings[0],
+		"~G_v1_Pod|apps|deploy may refer by name at spec.serviceAccountName"+
+			" to ~G_v1_ServiceAccount|apps|deployer, which moved to namespace 'ci'") {
+		t.Fatalf("unexpected warnings: %q", warnings)
+	}
+}
+
+func TestNamespaceRelocationTransformerService(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceRelocationTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: NamespaceRelocationTransformer
+metadata:
+  name: notImportantHere
+target:
+  kind: Service
+  name: webhook
+namespace: system
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: webhook
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: validator
+webhooks:
+- name: validate.example.com
+  clientConfig:
+    service:
+      name: webhook
+      namespace: default
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: webhook
+  namespace: system
+---
+apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: validator
+webhooks:
+- clientConfig:
+    service:
+      name: webhook
+      namespace: system
+  name: validate.example.com
+`)
+}
+
+func TestNamespaceRelocationTransformerConflict(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NamespaceRelocationTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: NamespaceRelocationTransformer
+metadata:
+  name: notImportantHere
+target:
+  kind: ServiceAccount
+  name: deployer
+  namespace: apps
+namespace: ci
+`, `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: deployer
+  namespace: apps
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: deployer
+  namespace: ci
+`,
+		func(t *testing.T, err error) {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), "since ~G_v1_ServiceAccount|ci|deployer exists") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+}
@@ -33,6 +33,7 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	if len(p.Namespace) == 0 {
 		return nil
 	}
+	owned := ownedServiceAccounts(m)
 	for _, r := range m.Resources() {
 		empty, err := r.IsEmpty()
 		if err != nil {
@@ -46,6 +47,9 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 		err = r.ApplyFilter(namespace.Filter{
 			Namespace: p.Namespace,
 			FsSlice:   p.FieldSpecs,
+			IsOwnedServiceAccount: func(namespace, name string) bool {
+				return owned[namespace+"/"+name]
+			},
 		})
 		if err != nil {
 			return err
@@ -58,3 +62,18 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	}
 	return nil
 }
+
+// ownedServiceAccounts returns the "namespace/name" of every
+// ServiceAccount in the map, as they are before the namespace
+// changes, so binding subjects can be checked against them.
+func ownedServiceAccounts(m resmap.ResMap) map[string]bool {
+	result := make(map[string]bool)
+	for _, r := range m.Resources() {
+		if r.GetKind() != "ServiceAccount" {
+			continue
+		}
+		id := r.CurId()
+		result[id.EffectiveNamespace()+"/"+id.Name] = true
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Place the pods of workloads on a pool of nodes, by adding
+// a nodeSelector and tolerations to their specs.  The entries
+// of an existing nodeSelector with other keys are kept, as are
+// existing tolerations; a toleration is only added if the pod
+// spec has none with the same key and effect.
+type plugin struct {
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+	Tolerations  []toleration      `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+	Target       *types.Selector   `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// toleration is a core/v1 Toleration.
+type toleration struct {
+	Key               string `json:"key,omitempty" yaml:"key,omitempty"`
+	Operator          string `json:"operator,omitempty" yaml:"operator,omitempty"`
+	Value             string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect            string `json:"effect,omitempty" yaml:"effect,omitempty"`
+	TolerationSeconds *int64 `json:"tolerationSeconds,omitempty" yaml:"tolerationSeconds,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.NodeSelector = nil
+	p.Tolerations = nil
+	p.Target = nil
+	err = k8syaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if len(p.NodeSelector) == 0 && len(p.Tolerations) == 0 {
+		return fmt.Errorf("must specify a nodeSelector or tolerations")
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) (err error) {
+	resources := m.Resources()
+	if p.Target != nil {
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		paths := resource.PodSpecPaths(r.GetKind())
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				return node, p.place(node, paths)
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) place(node *yaml.RNode, paths [][]string) error {
+	for _, path := range paths {
+		podSpec, err := node.Pipe(yaml.Lookup(path...))
+		if err != nil {
+			return err
+		}
+		if podSpec == nil || podSpec.YNode().Kind != yaml.MappingNode {
+			continue
+		}
+		if err = p.setNodeSelector(podSpec); err != nil {
+			return err
+		}
+		if err = p.addTolerations(podSpec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) setNodeSelector(podSpec *yaml.RNode) error {
+	if len(p.NodeSelector) == 0 {
+		return nil
+	}
+	selector, err := podSpec.Pipe(
+		yaml.LookupCreate(yaml.MappingNode, "nodeSelector"))
+	if err != nil {
+		return err
+	}
+	for _, k := range yaml.SortedMapKeys(p.NodeSelector) {
+		err = selector.PipeE(
+			yaml.SetField(k, yaml.NewStringRNode(p.NodeSelector[k])))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) addTolerations(podSpec *yaml.RNode) error {
+	if len(p.Tolerations) == 0 {
+		return nil
+	}
+	list, err := podSpec.Pipe(
+		yaml.LookupCreate(yaml.SequenceNode, "tolerations"))
+	if err != nil {
+		return err
+	}
+	for _, t := range p.Tolerations {
+		if hasToleration(list, t) {
+			continue
+		}
+		y, err := k8syaml.Marshal(t)
+		if err != nil {
+			return err
+		}
+		n, err := yaml.Parse(string(y))
+		if err != nil {
+			return err
+		}
+		if err = list.PipeE(yaml.Append(n.YNode())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasToleration returns true if the list holds a toleration
+// with the key and effect of the given one.
+func hasToleration(list *yaml.RNode, t toleration) bool {
+	elements, err := list.Elements()
+	if err != nil {
+		return false
+	}
+	for _, e := range elements {
+		if scalarValue(e, "key") == t.Key &&
+			scalarValue(e, "effect") == t.Effect {
+			return true
+		}
+	}
+	return false
+}
+
+func scalarValue(node *yaml.RNode, field string) string {
+	f := node.Field(field)
+	if f == nil || f.Value.YNode().Kind != yaml.ScalarNode {
+		return ""
+	}
+	return f.Value.YNode().Value
+}
@@ -0,0 +1,151 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestNodePlacementTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NodePlacementTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: NodePlacementTransformer
+metadata:
+  name: notImportantHere
+nodeSelector:
+  pool: batch
+tolerations:
+- key: dedicated
+  operator: Equal
+  value: batch
+  effect: NoSchedule
+- key: spot
+  operator: Exists
+  effect: NoExecute
+target:
+  kind: Deployment
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      nodeSelector:
+        kubernetes.io/os: linux
+      tolerations:
+      - key: spot
+        operator: Exists
+        effect: NoExecute
+      containers:
+      - name: worker
+        image: worker
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reporter
+spec:
+  template:
+    spec:
+      containers:
+      - name: reporter
+        image: reporter
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - name: debug
+    image: busybox
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: worker
+spec:
+  template:
+    spec:
+      containers:
+      - image: worker
+        name: worker
+      nodeSelector:
+        kubernetes.io/os: linux
+        pool: batch
+      tolerations:
+      - effect: NoExecute
+        key: spot
+        operator: Exists
+      - effect: NoSchedule
+        key: dedicated
+        operator: Equal
+        value: batch
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reporter
+spec:
+  template:
+    spec:
+      containers:
+      - image: reporter
+        name: reporter
+      nodeSelector:
+        pool: batch
+      tolerations:
+      - effect: NoSchedule
+        key: dedicated
+        operator: Equal
+        value: batch
+      - effect: NoExecute
+        key: spot
+        operator: Exists
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+  - image: busybox
+    name: debug
+`)
+}
+
+func TestNodePlacementTransformerNothingToPlace(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("NodePlacementTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckError(`
+apiVersion: builtin
+kind: NodePlacementTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+`,
+		func(t *testing.T, err error) {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), "must specify a nodeSelector or tolerations") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+}
@@ -6,12 +6,15 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"reflect"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/pkg/errors"
 	"sigs.k8s.io/kustomize/api/filters/patchjson6902"
 	"sigs.k8s.io/kustomize/api/ifc"
 	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
 	"sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/yaml"
 )
@@ -22,6 +25,16 @@ type plugin struct {
 	Target       *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
 	Path         string          `json:"path,omitempty" yaml:"path,omitempty"`
 	JsonOp       string          `json:"jsonOp,omitempty" yaml:"jsonOp,omitempty"`
+
+	// WarnOnNoOp, if true, logs a warning for every target the
+	// patch leaves unchanged, as such a patch is likely stale.
+	WarnOnNoOp bool `json:"warnOnNoOp,omitempty" yaml:"warnOnNoOp,omitempty"`
+
+	// ReportChanges, if true, notes the fields the patch
+	// changes in each target, for PatchReports.
+	ReportChanges bool `json:"reportChanges,omitempty" yaml:"reportChanges,omitempty"`
+
+	reports []types.PatchReport
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -81,13 +94,50 @@ func (p *plugin) Transform(m resmap.ResMap) error {
 	if err != nil {
 		return err
 	}
+	p.reports = nil
 	for _, res := range resources {
+		var before map[string]interface{}
+		if p.WarnOnNoOp || p.ReportChanges {
+			if before, err = res.Map(); err != nil {
+				return err
+			}
+		}
 		err = res.ApplyFilter(patchjson6902.Filter{
 			Patch: p.JsonOp,
 		})
 		if err != nil {
 			return err
 		}
+		if !p.WarnOnNoOp && !p.ReportChanges {
+			continue
+		}
+		after, err := res.Map()
+		if err != nil {
+			return err
+		}
+		if p.WarnOnNoOp && reflect.DeepEqual(before, after) {
+			log.Printf("patch %s left %s unchanged", p.source(), res.CurId())
+		}
+		if p.ReportChanges {
+			p.reports = append(p.reports, types.PatchReport{
+				Patch:  p.source(),
+				Target: res.CurId(),
+				Fields: resource.ChangedFields(before, after),
+			})
+		}
 	}
 	return nil
 }
+
+// PatchReports implements resmap.PatchReporter.
+func (p *plugin) PatchReports() []types.PatchReport {
+	return p.reports
+}
+
+// source describes where the patch came from, for messages.
+func (p *plugin) source() string {
+	if p.Path != "" {
+		return p.Path
+	}
+	return "inline patch"
+}
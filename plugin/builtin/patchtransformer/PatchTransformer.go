@@ -6,6 +6,8 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"reflect"
 	"strings"
 
 	jsonpatch "github.com/evanphx/json-patch"
@@ -22,6 +24,16 @@ type plugin struct {
 	Path         string          `json:"path,omitempty" yaml:"path,omitempty"`
 	Patch        string          `json:"patch,omitempty" yaml:"patch,omitempty"`
 	Target       *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+
+	// WarnOnNoOp, if true, logs a warning for every target the
+	// patch leaves unchanged, as such a patch is likely stale.
+	WarnOnNoOp bool `json:"warnOnNoOp,omitempty" yaml:"warnOnNoOp,omitempty"`
+
+	// ReportChanges, if true, notes the fields the patch
+	// changes in each target, for PatchReports.
+	ReportChanges bool `json:"reportChanges,omitempty" yaml:"reportChanges,omitempty"`
+
+	reports []types.PatchReport
 }
 
 //noinspection GoUnusedGlobalVariable
@@ -71,6 +83,7 @@ func (p *plugin) Config(
 }
 
 func (p *plugin) Transform(m resmap.ResMap) error {
+	p.reports = nil
 	if p.loadedPatch == nil {
 		return p.transformJson6902(m, p.decodedPatch)
 	} else {
@@ -88,13 +101,35 @@ func (p *plugin) transformStrategicMerge(m resmap.ResMap, patch *resource.Resour
 		if err != nil {
 			return err
 		}
-		return target.ApplySmPatch(patch)
+		before, err := p.snapshot(target)
+		if err != nil {
+			return err
+		}
+		if err = target.ApplySmPatch(patch); err != nil {
+			return err
+		}
+		return p.noteChanges(before, target)
 	}
 	selected, err := m.Select(*p.Target)
 	if err != nil {
 		return err
 	}
-	return m.ApplySmPatch(resource.MakeIdSet(selected), patch)
+	befores := make([]map[string]interface{}, len(selected))
+	for i, res := range selected {
+		if befores[i], err = p.snapshot(res); err != nil {
+			return err
+		}
+	}
+	err = m.ApplySmPatch(resource.MakeIdSet(selected), patch)
+	if err != nil {
+		return err
+	}
+	for i, res := range selected {
+		if err = p.noteChanges(befores[i], res); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // transformJson6902 applies the provided json6902 patch
@@ -109,16 +144,69 @@ func (p *plugin) transformJson6902(m resmap.ResMap, patch jsonpatch.Patch) error
 	}
 	for _, res := range resources {
 		res.StorePreviousId()
+		before, err := p.snapshot(res)
+		if err != nil {
+			return err
+		}
 		err = res.ApplyFilter(patchjson6902.Filter{
 			Patch: p.Patch,
 		})
 		if err != nil {
 			return err
 		}
+		if err = p.noteChanges(before, res); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// snapshot returns the content of the resource before patching,
+// if it's needed to warn about no-ops or to report changes.
+func (p *plugin) snapshot(res *resource.Resource) (map[string]interface{}, error) {
+	if !p.WarnOnNoOp && !p.ReportChanges {
+		return nil, nil
+	}
+	return res.Map()
+}
+
+// noteChanges compares the patched resource with its snapshot,
+// warning about no-ops and noting changes, as configured.
+func (p *plugin) noteChanges(
+	before map[string]interface{}, res *resource.Resource) error {
+	if !p.WarnOnNoOp && !p.ReportChanges {
+		return nil
+	}
+	after, err := res.Map()
+	if err != nil {
+		return err
+	}
+	if p.WarnOnNoOp && reflect.DeepEqual(before, after) {
+		log.Printf("patch %s left %s unchanged", p.source(), res.CurId())
+	}
+	if p.ReportChanges {
+		p.reports = append(p.reports, types.PatchReport{
+			Patch:  p.source(),
+			Target: res.CurId(),
+			Fields: resource.ChangedFields(before, after),
+		})
+	}
+	return nil
+}
+
+// PatchReports implements resmap.PatchReporter.
+func (p *plugin) PatchReports() []types.PatchReport {
+	return p.reports
+}
+
+// source describes where the patch came from, for messages.
+func (p *plugin) source() string {
+	if p.Path != "" {
+		return p.Path
+	}
+	return "inline patch"
+}
+
 // jsonPatchFromBytes loads a Json 6902 patch from
 // a bytes input
 func jsonPatchFromBytes(
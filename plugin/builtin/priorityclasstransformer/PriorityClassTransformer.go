@@ -0,0 +1,85 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Set the priorityClassName of the pods of workloads.
+// A priorityClassName that is already set is left alone,
+// unless Overwrite is true.
+type plugin struct {
+	PriorityClassName string          `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+	Overwrite         bool            `json:"overwrite,omitempty" yaml:"overwrite,omitempty"`
+	Target            *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.PriorityClassName = ""
+	p.Overwrite = false
+	p.Target = nil
+	err = k8syaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.PriorityClassName == "" {
+		return fmt.Errorf("must specify priorityClassName")
+	}
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) (err error) {
+	resources := m.Resources()
+	if p.Target != nil {
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		paths := resource.PodSpecPaths(r.GetKind())
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				return node, p.setPriorityClass(node, paths)
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) setPriorityClass(node *yaml.RNode, paths [][]string) error {
+	for _, path := range paths {
+		podSpec, err := node.Pipe(yaml.Lookup(path...))
+		if err != nil {
+			return err
+		}
+		if podSpec == nil || podSpec.YNode().Kind != yaml.MappingNode {
+			continue
+		}
+		if !p.Overwrite && podSpec.Field("priorityClassName") != nil {
+			continue
+		}
+		err = podSpec.PipeE(yaml.SetField(
+			"priorityClassName", yaml.NewStringRNode(p.PriorityClassName)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
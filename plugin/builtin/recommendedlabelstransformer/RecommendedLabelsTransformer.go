@@ -0,0 +1,46 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"sigs.k8s.io/kustomize/api/filters/labels"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/yaml"
+)
+
+// Add the recommended app.kubernetes.io labels with the
+// given values to the given field specifications.
+type plugin struct {
+	types.RecommendedLabels `json:",inline,omitempty" yaml:",inline,omitempty"`
+	FieldSpecs              []types.FieldSpec `json:"fieldSpecs,omitempty" yaml:"fieldSpecs,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.RecommendedLabels = types.RecommendedLabels{}
+	p.FieldSpecs = nil
+	return yaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	l := p.Labels()
+	if len(l) == 0 {
+		return nil
+	}
+	for _, r := range m.Resources() {
+		err := r.ApplyFilter(labels.Filter{
+			Labels:  l,
+			FsSlice: p.FieldSpecs,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestRecommendedLabelsTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("RecommendedLabelsTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: RecommendedLabelsTransformer
+metadata:
+  name: notImportantHere
+name: mysql
+partOf: wordpress
+fieldSpecs:
+- path: metadata/labels
+  create: true
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  labels:
+    app.kubernetes.io/name: old
+`, `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  labels:
+    app.kubernetes.io/name: mysql
+    app.kubernetes.io/part-of: wordpress
+  name: settings
+`)
+}
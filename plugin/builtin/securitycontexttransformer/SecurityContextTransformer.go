@@ -0,0 +1,138 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/kio"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// Harden the containers of workloads by giving their
+// securityContexts these defaults:
+//
+//   readOnlyRootFilesystem: true
+//   allowPrivilegeEscalation: false
+//   capabilities:
+//     drop:
+//     - ALL
+//
+// A field that is already set is left alone, so that
+// deliberate exceptions survive.  Init and ephemeral
+// containers are only hardened when asked for.
+type plugin struct {
+	Target                     *types.Selector `json:"target,omitempty" yaml:"target,omitempty"`
+	IncludeInitContainers      bool            `json:"includeInitContainers,omitempty" yaml:"includeInitContainers,omitempty"`
+	IncludeEphemeralContainers bool            `json:"includeEphemeralContainers,omitempty" yaml:"includeEphemeralContainers,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+func (p *plugin) Config(
+	_ *resmap.PluginHelpers, c []byte) (err error) {
+	p.Target = nil
+	p.IncludeInitContainers = false
+	p.IncludeEphemeralContainers = false
+	return k8syaml.Unmarshal(c, p)
+}
+
+func (p *plugin) Transform(m resmap.ResMap) (err error) {
+	resources := m.Resources()
+	if p.Target != nil {
+		resources, err = m.Select(*p.Target)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range resources {
+		paths := resource.PodSpecPaths(r.GetKind())
+		err = r.ApplyFilter(kio.FilterAll(yaml.FilterFunc(
+			func(node *yaml.RNode) (*yaml.RNode, error) {
+				return node, p.harden(node, paths)
+			})))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) containerListFields() []string {
+	result := []string{"containers"}
+	if p.IncludeInitContainers {
+		result = append(result, "initContainers")
+	}
+	if p.IncludeEphemeralContainers {
+		result = append(result, "ephemeralContainers")
+	}
+	return result
+}
+
+func (p *plugin) harden(node *yaml.RNode, paths [][]string) error {
+	for _, path := range paths {
+		podSpec, err := node.Pipe(yaml.Lookup(path...))
+		if err != nil {
+			return err
+		}
+		if podSpec == nil || podSpec.YNode().Kind != yaml.MappingNode {
+			continue
+		}
+		for _, f := range p.containerListFields() {
+			containers, err := podSpec.Pipe(yaml.Lookup(f))
+			if err != nil {
+				return err
+			}
+			if containers == nil {
+				continue
+			}
+			err = containers.VisitElements(hardenContainer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hardenContainer(container *yaml.RNode) error {
+	sc, err := container.Pipe(
+		yaml.LookupCreate(yaml.MappingNode, "securityContext"))
+	if err != nil {
+		return err
+	}
+	err = setIfUnset(sc, "readOnlyRootFilesystem", boolNode("true"))
+	if err != nil {
+		return err
+	}
+	err = setIfUnset(sc, "allowPrivilegeEscalation", boolNode("false"))
+	if err != nil {
+		return err
+	}
+	caps, err := sc.Pipe(
+		yaml.LookupCreate(yaml.MappingNode, "capabilities"))
+	if err != nil {
+		return err
+	}
+	return setIfUnset(caps, "drop", yaml.NewListRNode("ALL"))
+}
+
+func setIfUnset(node *yaml.RNode, field string, value *yaml.RNode) error {
+	if node.Field(field) != nil {
+		return nil
+	}
+	return node.PipeE(yaml.SetField(field, value))
+}
+
+func boolNode(v string) *yaml.RNode {
+	return yaml.NewRNode(&yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   yaml.NodeTagBool,
+		Value: v,
+	})
+}
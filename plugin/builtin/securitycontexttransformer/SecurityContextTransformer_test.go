@@ -0,0 +1,171 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestSecurityContextTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("SecurityContextTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: SecurityContextTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      initContainers:
+      - name: init
+        image: busybox
+      containers:
+      - name: app
+        image: nginx
+      - name: debug
+        image: busybox
+        securityContext:
+          readOnlyRootFilesystem: false
+          capabilities:
+            add:
+            - NET_ADMIN
+            drop: []
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+data:
+  spec: unrelated
+`, `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - image: nginx
+        name: app
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            drop:
+            - ALL
+          readOnlyRootFilesystem: true
+      - image: busybox
+        name: debug
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities:
+            add:
+            - NET_ADMIN
+            drop: []
+          readOnlyRootFilesystem: false
+      initContainers:
+      - image: busybox
+        name: init
+---
+apiVersion: v1
+data:
+  spec: unrelated
+kind: ConfigMap
+metadata:
+  name: config
+`)
+}
+
+func TestSecurityContextTransformerAllContainers(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("SecurityContextTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: SecurityContextTransformer
+metadata:
+  name: notImportantHere
+includeInitContainers: true
+includeEphemeralContainers: true
+target:
+  kind: Pod
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  initContainers:
+  - name: init
+  ephemeralContainers:
+  - name: debugger
+  containers:
+  - name: app
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: app
+`, `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+spec:
+  containers:
+  - name: app
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      readOnlyRootFilesystem: true
+  ephemeralContainers:
+  - name: debugger
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      readOnlyRootFilesystem: true
+  initContainers:
+  - name: init
+    securityContext:
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop:
+        - ALL
+      readOnlyRootFilesystem: true
+---
+apiVersion: batch/v1beta1
+kind: CronJob
+metadata:
+  name: job
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: app
+`)
+}
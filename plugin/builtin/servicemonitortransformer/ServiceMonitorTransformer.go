@@ -0,0 +1,159 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:generate pluginator
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/kustomize/api/resid"
+	"sigs.k8s.io/kustomize/api/resmap"
+	"sigs.k8s.io/kustomize/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	defaultScrapeAnnotation = "prometheus.io/scrape"
+	defaultPortAnnotation   = "prometheus.io/port"
+	defaultPathAnnotation   = "prometheus.io/path"
+	defaultMetricsPath      = "/metrics"
+)
+
+// Emit a prometheus-operator ServiceMonitor for every Service
+// annotated for scraping.  It's a transformer so that each
+// monitor can select its Service by the labels, name and
+// namespace that the Service ends up with.
+type plugin struct {
+	rf *resource.Factory
+
+	// ScrapeAnnotation must be "true" on a Service for it to
+	// get a ServiceMonitor.  Defaults to prometheus.io/scrape.
+	ScrapeAnnotation string `json:"scrapeAnnotation,omitempty" yaml:"scrapeAnnotation,omitempty"`
+
+	// PortAnnotation holds the port to scrape, either a port
+	// name or a number.  Defaults to prometheus.io/port.
+	PortAnnotation string `json:"portAnnotation,omitempty" yaml:"portAnnotation,omitempty"`
+
+	// PathAnnotation holds the path to scrape, defaulting
+	// to /metrics when absent.  Defaults to prometheus.io/path.
+	PathAnnotation string `json:"pathAnnotation,omitempty" yaml:"pathAnnotation,omitempty"`
+
+	// Labels to add to each ServiceMonitor, e.g. the label a
+	// Prometheus instance uses to select its monitors.
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+//noinspection GoUnusedGlobalVariable
+var KustomizePlugin plugin
+
+var serviceMonitorGvk = resid.Gvk{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+func (p *plugin) Config(
+	h *resmap.PluginHelpers, c []byte) (err error) {
+	p.ScrapeAnnotation = ""
+	p.PortAnnotation = ""
+	p.PathAnnotation = ""
+	p.Labels = nil
+	err = yaml.Unmarshal(c, p)
+	if err != nil {
+		return err
+	}
+	if p.ScrapeAnnotation == "" {
+		p.ScrapeAnnotation = defaultScrapeAnnotation
+	}
+	if p.PortAnnotation == "" {
+		p.PortAnnotation = defaultPortAnnotation
+	}
+	if p.PathAnnotation == "" {
+		p.PathAnnotation = defaultPathAnnotation
+	}
+	p.rf = h.ResmapFactory().RF()
+	return nil
+}
+
+func (p *plugin) Transform(m resmap.ResMap) error {
+	for _, r := range m.Resources() {
+		if r.GetKind() != "Service" {
+			continue
+		}
+		annotations := r.GetAnnotations()
+		if annotations[p.ScrapeAnnotation] != "true" {
+			continue
+		}
+		id := resid.NewResIdWithNamespace(
+			serviceMonitorGvk, r.GetName(), r.GetNamespace())
+		if _, err := m.GetByCurrentId(id); err == nil {
+			// Respect a ServiceMonitor that already exists.
+			continue
+		}
+		sm, err := p.makeServiceMonitor(r, annotations)
+		if err != nil {
+			return err
+		}
+		if err = m.Append(sm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *plugin) makeServiceMonitor(
+	svc *resource.Resource, annotations map[string]string) (*resource.Resource, error) {
+	selector := svc.GetLabels()
+	if len(selector) == 0 {
+		return nil, fmt.Errorf(
+			"service %s is annotated with %s but has no labels to select it by",
+			svc.CurId(), p.ScrapeAnnotation)
+	}
+	port, ok := annotations[p.PortAnnotation]
+	if !ok {
+		return nil, fmt.Errorf(
+			"service %s is annotated with %s but lacks annotation %s",
+			svc.CurId(), p.ScrapeAnnotation, p.PortAnnotation)
+	}
+	endpoint := map[string]interface{}{
+		"path": defaultMetricsPath,
+	}
+	if path, ok := annotations[p.PathAnnotation]; ok {
+		endpoint["path"] = path
+	}
+	if n, err := strconv.Atoi(port); err == nil {
+		endpoint["targetPort"] = n
+	} else {
+		endpoint["port"] = port
+	}
+	matchLabels := make(map[string]interface{}, len(selector))
+	for k, v := range selector {
+		matchLabels[k] = v
+	}
+	meta := map[string]interface{}{
+		"name": svc.GetName(),
+	}
+	if ns := svc.GetNamespace(); ns != "" {
+		meta["namespace"] = ns
+	}
+	if len(p.Labels) > 0 {
+		labels := make(map[string]interface{}, len(p.Labels))
+		for k, v := range p.Labels {
+			labels[k] = v
+		}
+		meta["labels"] = labels
+	}
+	return p.rf.FromMap(map[string]interface{}{
+		"apiVersion": serviceMonitorGvk.ApiVersion(),
+		"kind":       serviceMonitorGvk.Kind,
+		"metadata":   meta,
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": matchLabels,
+			},
+			"endpoints": []interface{}{endpoint},
+		},
+	}), nil
+}
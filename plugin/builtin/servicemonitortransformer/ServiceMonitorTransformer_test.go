@@ -0,0 +1,149 @@
+// Copyright 2021 The Kubernetes Authors.
+// SPDX-License-Identifier: Apache-2.0
+
+package main_test
+
+import (
+	"strings"
+	"testing"
+
+	kusttest_test "sigs.k8s.io/kustomize/api/testutils/kusttest"
+)
+
+func TestServiceMonitorTransformer(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ServiceMonitorTransformer")
+	defer th.Reset()
+
+	th.RunTransformerAndCheckResult(`
+apiVersion: builtin
+kind: ServiceMonitorTransformer
+metadata:
+  name: notImportantHere
+labels:
+  release: prometheus
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  namespace: shop
+  labels:
+    app: api
+  annotations:
+    prometheus.io/scrape: "true"
+    prometheus.io/port: http-metrics
+    prometheus.io/path: /stats
+spec:
+  ports:
+  - name: http-metrics
+    port: 9090
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: worker
+  labels:
+    app: worker
+  annotations:
+    prometheus.io/scrape: "true"
+    prometheus.io/port: "8080"
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+  labels:
+    app: db
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    prometheus.io/path: /stats
+    prometheus.io/port: http-metrics
+    prometheus.io/scrape: "true"
+  labels:
+    app: api
+  name: api
+  namespace: shop
+spec:
+  ports:
+  - name: http-metrics
+    port: 9090
+---
+apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    prometheus.io/port: "8080"
+    prometheus.io/scrape: "true"
+  labels:
+    app: worker
+  name: worker
+---
+apiVersion: v1
+kind: Service
+metadata:
+  labels:
+    app: db
+  name: db
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  labels:
+    release: prometheus
+  name: api
+  namespace: shop
+spec:
+  endpoints:
+  - path: /stats
+    port: http-metrics
+  selector:
+    matchLabels:
+      app: api
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  labels:
+    release: prometheus
+  name: worker
+spec:
+  endpoints:
+  - path: /metrics
+    targetPort: 8080
+  selector:
+    matchLabels:
+      app: worker
+`)
+}
+
+func TestServiceMonitorTransformerMissingPort(t *testing.T) {
+	th := kusttest_test.MakeEnhancedHarness(t).
+		PrepBuiltin("ServiceMonitorTransformer")
+	defer th.Reset()
+
+	err := th.ErrorFromLoadAndRunTransformer(`
+apiVersion: builtin
+kind: ServiceMonitorTransformer
+metadata:
+  name: notImportantHere
+`, `
+apiVersion: v1
+kind: Service
+metadata:
+  name: api
+  labels:
+    app: api
+  annotations:
+    prometheus.io/scrape: "true"
+`)
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if !strings.Contains(err.Error(), "lacks annotation prometheus.io/port") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}